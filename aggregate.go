@@ -0,0 +1,100 @@
+package retry
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Aggregate creates an Option that makes the retry process to return
+// the Errors holding errors of all attempts instead of the last one.
+// It is placed into the Err field of the ExhaustedError or CancelledError.
+func Aggregate() Option {
+	return func(cfg *options) {
+		cfg.aggregate = true
+	}
+}
+
+// AttemptError describes an error returned by an attempt.
+type AttemptError struct {
+	// Attempt is the zero-based number of the attempt.
+	Attempt uint
+	// Time is the time when the error occurred.
+	Time time.Time
+	// Err is the error returned by the attempt.
+	Err error
+}
+
+// Error returns a string representation of an error.
+func (err AttemptError) Error() string {
+	return "attempt #" + strconv.FormatUint(uint64(err.Attempt), 10) + ": " + err.Err.Error()
+}
+
+// Unwrap returns the error returned by the attempt.
+func (err AttemptError) Unwrap() error { return err.Err }
+
+// Errors holds errors returned by all attempts in order.
+//
+// It is compatible with errors.Is and errors.As,
+// which check all the errors it holds.
+type Errors struct {
+	Attempts []AttemptError
+}
+
+// Error returns a string representation of an error.
+func (errs *Errors) Error() string {
+	messages := make([]string, 0, len(errs.Attempts))
+	for _, err := range errs.Attempts {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors returned by all attempts.
+func (errs *Errors) Unwrap() []error {
+	list := make([]error, 0, len(errs.Attempts))
+	for _, err := range errs.Attempts {
+		list = append(list, err.Err)
+	}
+	return list
+}
+
+// Is reports whether any error returned by the attempts matches the target.
+func (errs *Errors) Is(target error) bool {
+	for _, err := range errs.Attempts {
+		if errors.Is(err.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error returned by the attempts that matches the target,
+// and if one is found, sets the target to that error value and returns true.
+func (errs *Errors) As(target interface{}) bool {
+	for _, err := range errs.Attempts {
+		if errors.As(err.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+type collector struct {
+	enabled bool
+	list    []AttemptError
+}
+
+func (c *collector) collect(attempt uint, err error) {
+	if c.enabled && err != nil {
+		c.list = append(c.list, AttemptError{Attempt: attempt, Time: time.Now(), Err: err})
+	}
+}
+
+func (c *collector) result(err error) error {
+	if !c.enabled || len(c.list) == 0 {
+		return err
+	}
+	return &Errors{Attempts: c.list}
+}
@@ -0,0 +1,122 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestAggregate(t *testing.T) {
+	var (
+		meaningful = &net.DNSError{Err: "no such host", Name: "host", IsNotFound: true}
+		failure    = errors.New("failure")
+	)
+
+	t.Run("disabled", func(t *testing.T) {
+		err := Do(breaker(), sequence(meaningful, failure), strategy.Limit(2))
+		if err.(*ExhaustedError).Err != failure {
+			t.Errorf("expected: %#v, obtained: %#v", failure, err)
+		}
+	})
+
+	t.Run("give up", func(t *testing.T) {
+		ctx := With(breaker(), Aggregate())
+		err := Do(ctx, sequence(meaningful, failure, context.DeadlineExceeded), strategy.Limit(3))
+
+		var errs *Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		if len(errs.Attempts) != 3 {
+			t.Fatalf("expected: %d, obtained: %d", 3, len(errs.Attempts))
+		}
+		for i, err := range errs.Attempts {
+			if err.Attempt != uint(i) || err.Time.IsZero() {
+				t.Errorf("unexpected attempt error: %#v", err)
+			}
+		}
+		if !errors.Is(err, failure) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Error("errors are not found")
+		}
+
+		var dns *net.DNSError
+		if !errors.As(err, &dns) || dns != meaningful {
+			t.Errorf("expected: %#v, obtained: %#v", meaningful, dns)
+		}
+		if expected, obtained := "attempt #0: lookup host: no such host; "+
+			"attempt #1: failure; attempt #2: context deadline exceeded", errs.Error(); expected != obtained {
+			t.Errorf("expected: %q, obtained: %q", expected, obtained)
+		}
+		if len(errs.Unwrap()) != 3 {
+			t.Error("unexpected unwrapped errors")
+		}
+	})
+
+	t.Run("breaker cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		action := func(context.Context) error {
+			cancel()
+			return meaningful
+		}
+		err := Do(With(ctx, Aggregate()), action, strategy.Wait(time.Hour))
+		if !errors.Is(err, context.Canceled) || !errors.Is(err, meaningful) {
+			t.Errorf("unexpected error: %#v", err)
+		}
+
+		var errs *Errors
+		if !errors.As(err, &errs) || len(errs.Attempts) != 1 {
+			t.Errorf("unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("breaker cancel in goroutine", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		var attempts int
+		action := func(context.Context) error {
+			if attempts++; attempts < 3 {
+				return []error{meaningful, failure}[attempts-1]
+			}
+			cancel()
+			<-release
+			return nil
+		}
+		err := Go(With(ctx, Aggregate()), action)
+		if !errors.Is(err, context.Canceled) || !errors.Is(err, meaningful) || !errors.Is(err, failure) {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if expected, obtained := "retry: cancelled after 3 attempts: context canceled: "+
+			"attempt #0: lookup host: no such host; attempt #1: failure", err.Error(); expected != obtained {
+			t.Errorf("expected: %q, obtained: %q", expected, obtained)
+		}
+	})
+
+	t.Run("no attempts", func(t *testing.T) {
+		err := Do(With(breaker(), Aggregate()), sequence(), strategy.Limit(0))
+		if err.(*ExhaustedError).Err != Error("have no any try") {
+			t.Errorf("unexpected error: %#v", err)
+		}
+
+		err = Do(With(interrupted(), Aggregate()), sequence())
+		if err.(*CancelledError).Err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		if err := Do(With(breaker(), Aggregate()), sequence(failure, nil), strategy.Limit(2)); err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+	})
+}
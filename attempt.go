@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Attempt carries metadata about the retry process that strategies
+// and the action can read from the Breaker they received.
+//
+//  how := retry.How{
+//  	func(breaker strategy.Breaker, attempt uint, err error) bool {
+//  		info, _ := retry.Info(breaker)
+//  		return info.Elapsed() < time.Minute
+//  	},
+//  }
+//
+type Attempt struct {
+	// Number is the zero-based number of the current attempt.
+	Number uint
+	// Start is the time when the retry process has been started.
+	Start time.Time
+	// Previous is the start time of the previous attempt.
+	// It is zero before the first attempt.
+	Previous time.Time
+	// Duration is the time spent by the previous attempt.
+	Duration time.Duration
+	// Wait is the time spent by strategies before the current attempt.
+	// It is zero while the strategies are evaluated.
+	Wait time.Duration
+	// Delay is the cumulative time spent by strategies.
+	Delay time.Duration
+	// Err is the raw error returned by the previous attempt.
+	Err error
+	// Cause is the unwrapped Err, the same value that strategies receive.
+	Cause error
+}
+
+// Elapsed returns the time passed since the retry process has been started.
+func (attempt Attempt) Elapsed() time.Duration {
+	return time.Since(attempt.Start)
+}
+
+// Info returns metadata about the current attempt if the breaker
+// was passed by Do or Go into a strategy or an action.
+func Info(breaker Breaker) (Attempt, bool) {
+	ctx, is := breaker.(context.Context)
+	if !is {
+		return Attempt{}, false
+	}
+	current, is := ctx.Value(frameKey{}).(frame)
+	return current.attempt, is && current.active
+}
@@ -0,0 +1,79 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestInfo(t *testing.T) {
+	t.Run("outside of the retry process", func(t *testing.T) {
+		if _, is := Info(context.TODO()); is {
+			t.Error("unexpected metadata")
+		}
+		if _, is := Info(make(signal)); is {
+			t.Error("unexpected metadata")
+		}
+	})
+
+	t.Run("strategies and action", func(t *testing.T) {
+		var (
+			seen    []Attempt
+			actions []Attempt
+			root    = errors.New("failure")
+			failure = layer{root}
+		)
+		observe := func(breaker strategy.Breaker, attempt uint, err error) bool {
+			info, is := Info(breaker)
+			if !is {
+				t.Fatal("metadata is not available")
+			}
+			if info.Number != attempt {
+				t.Errorf("expected: %d, obtained: %d", attempt, info.Number)
+			}
+			if attempt > 0 && info.Cause != err {
+				t.Errorf("expected: %#v, obtained: %#v", err, info.Cause)
+			}
+			seen = append(seen, info)
+			return true
+		}
+		action := func(ctx context.Context) error {
+			info, is := Info(ctx)
+			if !is {
+				t.Fatal("metadata is not available")
+			}
+			actions = append(actions, info)
+			time.Sleep(time.Millisecond)
+			return failure
+		}
+
+		_ = Do(breaker(), action, strategy.Limit(3), observe, strategy.Wait(time.Millisecond))
+		if len(seen) != 3 || len(actions) != 3 {
+			t.Fatalf("unexpected number of attempts: %d, %d", len(seen), len(actions))
+		}
+
+		first, last := actions[0], actions[2]
+		if first.Err != nil || !first.Previous.IsZero() || first.Duration != 0 {
+			t.Errorf("unexpected metadata of the first attempt: %#v", first)
+		}
+		if last.Err != failure || last.Cause != root {
+			t.Errorf("unexpected errors: %#v, %#v", last.Err, last.Cause)
+		}
+		if last.Duration < time.Millisecond || last.Previous.Before(first.Start) {
+			t.Errorf("unexpected timing of the previous attempt: %#v", last)
+		}
+		if last.Wait < time.Millisecond || last.Delay < 2*time.Millisecond {
+			t.Errorf("unexpected delay: %s, %s", last.Wait, last.Delay)
+		}
+		if seen[2].Wait != 0 || seen[2].Delay != actions[1].Delay {
+			t.Errorf("unexpected delay seen by strategies: %#v", seen[2])
+		}
+		if last.Start != first.Start || last.Elapsed() < 3*time.Millisecond {
+			t.Errorf("unexpected start time: %#v", last)
+		}
+	})
+}
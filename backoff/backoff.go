@@ -1,19 +1,26 @@
-// Package backoff provides stateless methods of calculating durations based on
+// Package backoff provides methods of calculating durations based on
 // a number of attempts made.
 package backoff
 
 import (
 	"math"
 	"time"
+
+	"github.com/kamilsk/retry/v5/jitter"
 )
 
 // Algorithm defines a function that calculates a time.Duration based on
 // the given retry attempt number.
 type Algorithm = func(attempt uint) time.Duration
 
+// MaxDuration is the largest duration that Algorithms return
+// instead of overflowing.
+const MaxDuration = time.Duration(math.MaxInt64)
+
 // Constant creates an Algorithm that returns the initial duration
 // by the all time.
 func Constant(duration time.Duration) Algorithm {
+	duration = clamp(duration)
 	return func(uint) time.Duration {
 		return duration
 	}
@@ -23,7 +30,14 @@ func Constant(duration time.Duration) Algorithm {
 // by the given increment for each attempt.
 func Incremental(initial, increment time.Duration) Algorithm {
 	return func(attempt uint) time.Duration {
-		return initial + (increment * time.Duration(attempt))
+		if increment < 0 {
+			decrement := add(multiply(-(increment+1), uint64(attempt)), time.Duration(attempt))
+			if decrement >= initial {
+				return 0
+			}
+			return initial - decrement
+		}
+		return add(initial, multiply(increment, uint64(attempt)))
 	}
 }
 
@@ -38,7 +52,14 @@ func Linear(factor time.Duration) Algorithm {
 // calculated as the given base raised to the attempt number.
 func Exponential(factor time.Duration, base float64) Algorithm {
 	return func(attempt uint) time.Duration {
-		return factor * time.Duration(math.Pow(base, float64(attempt)))
+		n := math.Pow(base, float64(attempt))
+		if n >= math.MaxUint64 {
+			return multiply(factor, math.MaxUint64)
+		}
+		if n < 0 || math.IsNaN(n) {
+			return 0
+		}
+		return multiply(factor, uint64(n))
 	}
 }
 
@@ -54,14 +75,107 @@ func BinaryExponential(factor time.Duration) Algorithm {
 // the Fibonacci sequence.
 func Fibonacci(factor time.Duration) Algorithm {
 	return func(attempt uint) time.Duration {
-		n := attempt
+		n := uint64(attempt)
 		if n != 0 {
-			var a, b uint = 0, 1
+			var a, b uint64 = 0, 1
 			for i := uint(1); i < attempt; i++ {
+				if b > math.MaxUint64-a {
+					b = math.MaxUint64
+					break
+				}
 				a, b = b, a+b
 			}
 			n = b
 		}
-		return factor * time.Duration(n)
+		return multiply(factor, n)
+	}
+}
+
+// Cap creates an Algorithm that limits durations calculated
+// by the given Algorithm by the max duration.
+func Cap(algorithm Algorithm, max time.Duration) Algorithm {
+	return MinMax(algorithm, 0, max)
+}
+
+// MinMax creates an Algorithm that clamps durations calculated
+// by the given Algorithm into the [min, max] range.
+func MinMax(algorithm Algorithm, min, max time.Duration) Algorithm {
+	return func(attempt uint) time.Duration {
+		duration := algorithm(attempt)
+		if duration > max {
+			duration = max
+		}
+		if duration < min {
+			duration = min
+		}
+		return clamp(duration)
+	}
+}
+
+func add(a, b time.Duration) time.Duration {
+	if b > 0 && a > MaxDuration-b {
+		return MaxDuration
+	}
+	return clamp(a + b)
+}
+
+func clamp(duration time.Duration) time.Duration {
+	if duration < 0 {
+		return 0
+	}
+	return duration
+}
+
+func multiply(factor time.Duration, n uint64) time.Duration {
+	if factor <= 0 || n == 0 {
+		return 0
+	}
+	if n > uint64(MaxDuration/factor) {
+		return MaxDuration
+	}
+	return factor * time.Duration(n)
+}
+
+// Factory defines a function that creates a stateful Algorithm,
+// which calculates a time.Duration based on the previous ones.
+// An Algorithm is created for each retry process, so concurrent
+// processes don't share the state.
+type Factory = func() Algorithm
+
+// DecorrelatedJitter creates a Factory of Algorithms that calculate a random
+// duration in [base, previous*3), where previous is the last calculated duration
+// starting from the base, and cap the result by the given max duration.
+// The base is at least one nanosecond, so the duration can grow,
+// and the max is at least the base.
+//
+// The given generator is what is used to determine the random duration.
+// It is shared by all created Algorithms, so wrap it by jitter.Locked
+// or use jitter.Pooled if they run concurrently.
+//
+// Inspired by https://www.awsarchitectureblog.com/2015/03/backoff.html
+func DecorrelatedJitter(base, max time.Duration, generator jitter.Generator) Factory {
+	if base < time.Nanosecond {
+		base = time.Nanosecond
+	}
+	if max < base {
+		max = base
+	}
+	return func() Algorithm {
+		previous := base
+		return func(uint) time.Duration {
+			upper := max
+			if previous < max/3 {
+				upper = previous * 3
+			}
+			next := base
+			if upper > base {
+				next += time.Duration(generator.Int63n(int64(upper - base)))
+			}
+			if next > max {
+				next = max
+			}
+			previous = next
+			return next
+		}
 	}
 }
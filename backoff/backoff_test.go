@@ -2,7 +2,9 @@ package backoff_test
 
 import (
 	"math"
+	"math/rand"
 	"testing"
+	"testing/quick"
 	"time"
 
 	. "github.com/kamilsk/retry/v5/backoff"
@@ -104,3 +106,178 @@ func TestFibonacci(t *testing.T) {
 		_ = Fibonacci(time.Millisecond)(50)
 	})
 }
+
+func TestCap(t *testing.T) {
+	const max = time.Second
+
+	algorithm := Cap(BinaryExponential(time.Millisecond), max)
+
+	for i := uint(0); i < 100; i++ {
+		result := algorithm(i)
+		expected := time.Millisecond << i
+		if i >= 10 {
+			expected = max
+		}
+
+		if result != expected {
+			t.Errorf("algorithm expected to return a %s duration, but received %s instead", expected, result)
+		}
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	const min, max = 10 * time.Millisecond, time.Second
+
+	algorithm := MinMax(Linear(time.Millisecond), min, max)
+
+	tests := map[uint]time.Duration{
+		0:    min,
+		5:    min,
+		10:   min,
+		500:  500 * time.Millisecond,
+		1000: max,
+		5000: max,
+	}
+	for attempt, expected := range tests {
+		if result := algorithm(attempt); result != expected {
+			t.Errorf("algorithm expected to return a %s duration at %d, but received %s instead", expected, attempt, result)
+		}
+	}
+
+	if result := MinMax(Constant(-time.Second), -time.Second, max)(0); result != 0 {
+		t.Errorf("algorithm expected to return a non-negative duration, but received %s instead", result)
+	}
+}
+
+func TestSaturation(t *testing.T) {
+	growing := map[string]Algorithm{
+		"constant":           Constant(time.Hour),
+		"incremental":        Incremental(time.Hour, time.Hour),
+		"linear":             Linear(time.Hour),
+		"exponential":        Exponential(time.Hour, 1.5),
+		"binary exponential": BinaryExponential(time.Hour),
+		"fibonacci":          Fibonacci(time.Hour),
+		"cap":                Cap(BinaryExponential(time.Hour), 24*time.Hour),
+		"min max":            MinMax(Fibonacci(time.Hour), time.Minute, 24*time.Hour),
+	}
+	for name, algorithm := range growing {
+		algorithm := algorithm
+		t.Run(name, func(t *testing.T) {
+			monotonic := func(a, b uint32) bool {
+				if a > b {
+					a, b = b, a
+				}
+				x, y := algorithm(uint(a)), algorithm(uint(b))
+				return x >= 0 && y >= 0 && x <= y
+			}
+			if err := quick.Check(monotonic, nil); err != nil {
+				t.Error(err)
+			}
+
+			previous := time.Duration(0)
+			for _, attempt := range boundaries() {
+				result := algorithm(attempt)
+				if result < previous {
+					t.Errorf("algorithm returned %s at %d after %s", result, attempt, previous)
+				}
+				previous = result
+			}
+		})
+	}
+
+	shrinking := map[string]Algorithm{
+		"negative constant":  Constant(-time.Hour),
+		"negative increment": Incremental(time.Hour, -time.Minute),
+		"minimal increment":  Incremental(time.Hour, math.MinInt64),
+		"negative factor":    Exponential(-time.Hour, 2),
+		"fractional base":    Exponential(time.Hour, 0.5),
+		"negative base":      Exponential(time.Hour, -2),
+		"negative fibonacci": Fibonacci(-time.Hour),
+		"zero jitter base":   DecorrelatedJitter(0, time.Hour, rand.New(rand.NewSource(0)))(),
+		"negative jitter":    DecorrelatedJitter(-time.Hour, -time.Minute, rand.New(rand.NewSource(0)))(),
+	}
+	for name, algorithm := range shrinking {
+		algorithm := algorithm
+		t.Run(name, func(t *testing.T) {
+			nonNegative := func(attempt uint32) bool {
+				return algorithm(uint(attempt)) >= 0
+			}
+			if err := quick.Check(nonNegative, nil); err != nil {
+				t.Error(err)
+			}
+
+			for _, attempt := range boundaries() {
+				if result := algorithm(attempt); result < 0 {
+					t.Errorf("algorithm returned %s at %d", result, attempt)
+				}
+			}
+		})
+	}
+
+	if result := BinaryExponential(time.Nanosecond)(math.MaxUint32); result != MaxDuration {
+		t.Errorf("algorithm expected to saturate, but returned %s", result)
+	}
+}
+
+func TestDecorrelatedJitter(t *testing.T) {
+	const base = time.Millisecond
+	const max = time.Second
+
+	factory := DecorrelatedJitter(base, max, rand.New(rand.NewSource(0)))
+
+	first, second := factory(), factory()
+	previous, greatest := base, base
+	for i := uint(1); i < 50; i++ {
+		result := first(i)
+
+		if result < base || result > max || result >= previous*3 && result != base {
+			t.Errorf("algorithm returned an unexpected duration %s after %s", result, previous)
+		}
+		if result > greatest {
+			greatest = result
+		}
+		previous = result
+	}
+	if greatest < base*9 {
+		t.Errorf("algorithm expected to grow, but returned at most %s", greatest)
+	}
+
+	if result := second(1); result >= base*3 {
+		t.Errorf("algorithm expected to be independent, but returned %s", result)
+	}
+
+	t.Run("zero base", func(t *testing.T) {
+		algorithm := DecorrelatedJitter(0, max, rand.New(rand.NewSource(0)))()
+		greatest := time.Duration(0)
+		for i := uint(1); i < 100; i++ {
+			if result := algorithm(i); result > greatest {
+				greatest = result
+			}
+		}
+		if greatest < time.Microsecond {
+			t.Errorf("algorithm expected to grow, but returned at most %s", greatest)
+		}
+	})
+
+	t.Run("base equal to max", func(t *testing.T) {
+		algorithm := DecorrelatedJitter(max, max, rand.New(rand.NewSource(0)))()
+		for i := uint(1); i < 5; i++ {
+			if result := algorithm(i); result != max {
+				t.Errorf("algorithm expected to return a %s duration, but received %s instead", max, result)
+			}
+		}
+	})
+}
+
+// helpers
+
+func boundaries() []uint {
+	attempts := make([]uint, 0, 128)
+	for i := uint(0); i < 64; i++ {
+		attempts = append(attempts, i)
+	}
+	for i := uint(64); i < math.MaxUint32/2; i *= 2 {
+		attempts = append(attempts, i-1, i, i+1)
+	}
+	return append(attempts, math.MaxUint32-1, math.MaxUint32)
+}
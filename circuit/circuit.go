@@ -0,0 +1,282 @@
+// Package circuit provides a circuit breaker that stops calling
+// a failing dependency and can be plugged into the retry process.
+package circuit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/clock"
+)
+
+// ErrOpen is a sentinel to check that the action was not performed
+// because the circuit is open, see OpenError.
+const ErrOpen retry.Error = "circuit: breaker is open"
+
+// OpenError is returned by a protected action when the circuit is open.
+type OpenError struct {
+	// Until is the time when the circuit allows the next probe.
+	Until time.Time
+}
+
+// Error returns a string representation of an error.
+func (err *OpenError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrOpen, err.Until.Format(time.RFC3339Nano))
+}
+
+// Is reports whether the target is the ErrOpen.
+func (err *OpenError) Is(target error) bool { return target == ErrOpen }
+
+// State defines a state of the circuit.
+type State uint8
+
+// The states of the circuit.
+const (
+	// Closed state allows actions to be performed.
+	Closed State = iota
+	// Open state fails actions fast without performing them.
+	Open
+	// HalfOpen state allows a limited number of probes to be performed.
+	HalfOpen
+)
+
+// String returns a string representation of the state.
+func (state State) String() string {
+	switch state {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("State(%d)", uint8(state))
+	}
+}
+
+// Circuit is a circuit breaker that tracks failure rate over a sliding
+// window of the last outcomes. It opens when the rate reaches the threshold,
+// fails fast during the cool-down period, and then allows probes to decide
+// whether to close again.
+//
+//  cb := &circuit.Circuit{Threshold: 0.5, CoolDown: time.Second}
+//
+//  err := retry.Do(ctx, cb.Protect(action),
+//  	cb.Strategy,
+//  	strategy.Limit(3),
+//  )
+//
+// The protected action records outcomes and returns the OpenError without
+// performing the action when the circuit is open. The Strategy halts
+// the retry process as soon as the circuit is open.
+// It is safe for concurrent use.
+type Circuit struct {
+	// Window is the number of the last outcomes taken into account.
+	// The default is 20.
+	Window int
+	// MinRequests is the number of outcomes required to evaluate the failure rate.
+	// The default is the half of the Window.
+	MinRequests int
+	// Threshold is the failure rate that opens the circuit.
+	// The default is 0.5.
+	Threshold float64
+	// CoolDown is the time during which the circuit stays open.
+	// The default is 5 seconds.
+	CoolDown time.Duration
+	// Probes is the number of concurrent actions allowed in the half-open state.
+	// The default is 1.
+	Probes int
+	// OnStateChange is called on each state transition if defined.
+	// It's called under the lock, so it must not call the Circuit.
+	OnStateChange func(from, to State)
+	// Clock is the source of time. The default is the clock.System.
+	Clock clock.Clock
+
+	mu       sync.Mutex
+	state    State
+	outcomes []bool
+	next     int
+	size     int
+	failures int
+	until    time.Time
+	probes   int
+}
+
+// State returns the current state of the circuit.
+func (circuit *Circuit) State() State {
+	circuit.mu.Lock()
+	defer circuit.mu.Unlock()
+
+	circuit.expire(circuit.now())
+	return circuit.state
+}
+
+// Protect wraps the action to record its outcomes and to fail fast
+// with the OpenError when the circuit is open. An action cancelled
+// by the context.Canceled is not recorded and doesn't change the state.
+func (circuit *Circuit) Protect(action func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		probe, err := circuit.acquire(circuit.now())
+		if err != nil {
+			return err
+		}
+		err = action(ctx)
+		circuit.release(probe, err)
+		return err
+	}
+}
+
+// Strategy is a Strategy that halts the retry process when the circuit is open.
+func (circuit *Circuit) Strategy(_ retry.Breaker, attempt uint, err error) bool {
+	if attempt == 0 {
+		return true
+	}
+	var open *OpenError
+	return !errors.As(err, &open) && circuit.State() != Open
+}
+
+func (circuit *Circuit) acquire(now time.Time) (bool, error) {
+	circuit.mu.Lock()
+	defer circuit.mu.Unlock()
+
+	circuit.expire(now)
+	switch circuit.state {
+	case Open:
+		return false, &OpenError{Until: circuit.until}
+	case HalfOpen:
+		if circuit.probes >= circuit.limit() {
+			return false, &OpenError{Until: circuit.until}
+		}
+		circuit.probes++
+		return true, nil
+	}
+	return false, nil
+}
+
+func (circuit *Circuit) release(probe bool, err error) {
+	circuit.mu.Lock()
+	defer circuit.mu.Unlock()
+
+	if probe && circuit.state == HalfOpen && circuit.probes > 0 {
+		circuit.probes--
+	}
+	// a cancelled action says nothing about the dependency
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	failed := err != nil
+	switch {
+	case probe && circuit.state == HalfOpen:
+		if failed {
+			circuit.open(circuit.now())
+			return
+		}
+		circuit.switchTo(Closed)
+		circuit.reset()
+	case !probe && circuit.state == Closed:
+		circuit.record(failed)
+		if circuit.size >= circuit.minimum() &&
+			float64(circuit.failures)/float64(circuit.size) >= circuit.threshold() {
+			circuit.open(circuit.now())
+		}
+	}
+}
+
+func (circuit *Circuit) record(failed bool) {
+	if circuit.outcomes == nil {
+		circuit.outcomes = make([]bool, circuit.window())
+	}
+	if circuit.size == len(circuit.outcomes) {
+		if circuit.outcomes[circuit.next] {
+			circuit.failures--
+		}
+	} else {
+		circuit.size++
+	}
+	circuit.outcomes[circuit.next] = failed
+	if failed {
+		circuit.failures++
+	}
+	circuit.next = (circuit.next + 1) % len(circuit.outcomes)
+}
+
+func (circuit *Circuit) reset() {
+	circuit.outcomes, circuit.next, circuit.size, circuit.failures = nil, 0, 0, 0
+}
+
+func (circuit *Circuit) open(now time.Time) {
+	circuit.until = now.Add(circuit.coolDown())
+	circuit.switchTo(Open)
+	circuit.reset()
+}
+
+func (circuit *Circuit) expire(now time.Time) {
+	if circuit.state == Open && !now.Before(circuit.until) {
+		circuit.probes = 0
+		circuit.switchTo(HalfOpen)
+	}
+}
+
+func (circuit *Circuit) switchTo(state State) {
+	if circuit.state == state {
+		return
+	}
+	from := circuit.state
+	circuit.state = state
+	if circuit.OnStateChange != nil {
+		circuit.OnStateChange(from, state)
+	}
+}
+
+func (circuit *Circuit) now() time.Time {
+	if circuit.Clock == nil {
+		return clock.System.Now()
+	}
+	return circuit.Clock.Now()
+}
+
+const (
+	defaultWindow    = 20
+	defaultThreshold = 0.5
+	defaultCoolDown  = 5 * time.Second
+)
+
+func (circuit *Circuit) window() int {
+	if circuit.Window <= 0 {
+		return defaultWindow
+	}
+	return circuit.Window
+}
+
+func (circuit *Circuit) minimum() int {
+	if circuit.MinRequests <= 0 {
+		return (circuit.window() + 1) / 2
+	}
+	return circuit.MinRequests
+}
+
+func (circuit *Circuit) threshold() float64 {
+	if circuit.Threshold <= 0 {
+		return defaultThreshold
+	}
+	return circuit.Threshold
+}
+
+func (circuit *Circuit) coolDown() time.Duration {
+	if circuit.CoolDown <= 0 {
+		return defaultCoolDown
+	}
+	return circuit.CoolDown
+}
+
+func (circuit *Circuit) limit() int {
+	if circuit.Probes <= 0 {
+		return 1
+	}
+	return circuit.Probes
+}
@@ -0,0 +1,183 @@
+package circuit_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+	. "github.com/kamilsk/retry/v5/circuit"
+	"github.com/kamilsk/retry/v5/clock/clocktest"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestCircuit(t *testing.T) {
+	failure := errors.New("failure")
+
+	t.Run("state transitions", func(t *testing.T) {
+		var transitions []string
+		fake := clocktest.New(time.Now())
+		circuit := &Circuit{
+			Clock:       fake,
+			Window:      4,
+			MinRequests: 3,
+			Threshold:   0.5,
+			CoolDown:    time.Minute,
+			OnStateChange: func(from, to State) {
+				transitions = append(transitions, from.String()+" > "+to.String())
+			},
+		}
+
+		var calls int
+		fail := circuit.Protect(func(context.Context) error { calls++; return failure })
+		pass := circuit.Protect(func(context.Context) error { calls++; return nil })
+
+		_ = pass(context.TODO())
+		_ = fail(context.TODO())
+		if circuit.State() != Closed {
+			t.Fatal("circuit is open too early")
+		}
+		_ = fail(context.TODO())
+		if circuit.State() != Open {
+			t.Fatal("circuit is not open")
+		}
+
+		err := pass(context.TODO())
+		if !errors.Is(err, ErrOpen) || calls != 3 {
+			t.Fatalf("action is performed: %v", err)
+		}
+
+		fake.Advance(time.Minute)
+		if circuit.State() != HalfOpen {
+			t.Fatal("circuit is not half-open")
+		}
+		_ = fail(context.TODO())
+		if circuit.State() != Open {
+			t.Fatal("circuit is not open after failed probe")
+		}
+
+		fake.Advance(time.Minute)
+		if err := pass(context.TODO()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if circuit.State() != Closed {
+			t.Fatal("circuit is not closed after successful probe")
+		}
+
+		expected := []string{
+			"closed > open",
+			"open > half-open",
+			"half-open > open",
+			"open > half-open",
+			"half-open > closed",
+		}
+		if !reflect.DeepEqual(expected, transitions) {
+			t.Errorf("expected: %q, obtained: %q", expected, transitions)
+		}
+	})
+
+	t.Run("limited probes", func(t *testing.T) {
+		fake := clocktest.New(time.Now())
+		circuit := &Circuit{Window: 1, CoolDown: time.Minute, Clock: fake}
+		_ = circuit.Protect(func(context.Context) error { return failure })(context.TODO())
+		fake.Advance(time.Minute)
+
+		release := make(chan struct{})
+		started := make(chan struct{})
+		go func() {
+			_ = circuit.Protect(func(context.Context) error {
+				close(started)
+				<-release
+				return nil
+			})(context.TODO())
+		}()
+		<-started
+
+		err := circuit.Protect(func(context.Context) error { return nil })(context.TODO())
+		if !errors.Is(err, ErrOpen) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		close(release)
+	})
+
+	t.Run("cancellation is not a failure", func(t *testing.T) {
+		circuit := &Circuit{Window: 1}
+		_ = circuit.Protect(func(context.Context) error { return context.Canceled })(context.TODO())
+		if circuit.State() != Closed {
+			t.Error("circuit is open")
+		}
+	})
+
+	t.Run("cancellation is not a success", func(t *testing.T) {
+		var transitions []string
+		fake := clocktest.New(time.Now())
+		circuit := &Circuit{
+			Clock:       fake,
+			Window:      2,
+			MinRequests: 2,
+			CoolDown:    time.Minute,
+			OnStateChange: func(from, to State) {
+				transitions = append(transitions, from.String()+" > "+to.String())
+			},
+		}
+		fail := circuit.Protect(func(context.Context) error { return failure })
+		cancel := circuit.Protect(func(context.Context) error { return context.Canceled })
+
+		_ = fail(context.TODO())
+		_ = cancel(context.TODO())
+		if circuit.State() != Closed {
+			t.Fatal("cancellation is recorded as a failure")
+		}
+		_ = fail(context.TODO())
+		if circuit.State() != Open {
+			t.Fatal("cancellation is recorded as a success")
+		}
+
+		fake.Advance(time.Minute)
+		_ = cancel(context.TODO())
+		if circuit.State() != HalfOpen {
+			t.Fatal("cancelled probe changes the state")
+		}
+		if err := fail(context.TODO()); errors.Is(err, ErrOpen) {
+			t.Fatal("cancelled probe holds the slot")
+		}
+		if circuit.State() != Open {
+			t.Fatal("circuit is not open after failed probe")
+		}
+
+		expected := []string{"closed > open", "open > half-open", "half-open > open"}
+		if !reflect.DeepEqual(expected, transitions) {
+			t.Errorf("expected: %q, obtained: %q", expected, transitions)
+		}
+	})
+
+	t.Run("retry integration", func(t *testing.T) {
+		circuit := &Circuit{Window: 2, MinRequests: 2, CoolDown: time.Hour}
+
+		var calls int
+		action := circuit.Protect(func(context.Context) error { calls++; return failure })
+		how := retry.How{circuit.Strategy, strategy.Limit(10)}
+
+		err := retry.Do(context.TODO(), action, how...)
+		if !errors.Is(err, failure) || calls != 2 {
+			t.Errorf("unexpected result: %v, %d calls", err, calls)
+		}
+
+		err = retry.Do(context.TODO(), action, how...)
+		var open *OpenError
+		if !errors.As(err, &open) || !errors.Is(err, ErrOpen) || calls != 2 {
+			t.Errorf("unexpected result: %v, %d calls", err, calls)
+		}
+		if open != nil && time.Until(open.Until) < 59*time.Minute {
+			t.Errorf("unexpected cool-down: %s", open.Until)
+		}
+	})
+
+	t.Run("state string", func(t *testing.T) {
+		if expected, obtained := "State(9)", State(9).String(); expected != obtained {
+			t.Errorf("expected: %q, obtained: %q", expected, obtained)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+// Package clock provides an abstraction of time
+// to make time-based strategies testable.
+package clock
+
+import "time"
+
+// Clock provides the current time and timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a new Timer that will send the current time
+	// on its channel after at least the given duration.
+	NewTimer(duration time.Duration) Timer
+}
+
+// Timer represents a single event, like the built-in time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing.
+	// It returns false if the timer has already expired or been stopped.
+	Stop() bool
+}
+
+// System is the Clock based on the built-in time package.
+var System Clock = system{}
+
+type system struct{}
+
+func (system) Now() time.Time { return time.Now() }
+
+func (system) NewTimer(duration time.Duration) Timer {
+	return timer{time.NewTimer(duration)}
+}
+
+type timer struct{ *time.Timer }
+
+func (timer timer) C() <-chan time.Time { return timer.Timer.C }
@@ -0,0 +1,30 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/v5/clock"
+)
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	if now := System.Now(); now.Before(before) {
+		t.Errorf("unexpected time: %s", now)
+	}
+
+	timer := System.NewTimer(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Error("timer is not fired")
+	}
+	if timer.Stop() {
+		t.Error("fired timer is stopped")
+	}
+
+	timer = System.NewTimer(time.Hour)
+	if !timer.Stop() {
+		t.Error("timer is not stopped")
+	}
+}
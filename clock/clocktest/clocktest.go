@@ -0,0 +1,121 @@
+// Package clocktest provides a fake clock for testing time-based strategies.
+package clocktest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kamilsk/retry/v5/clock"
+)
+
+// Clock is a fake clock that only moves forward when advanced manually.
+// It is safe for concurrent use.
+//
+//  fake := clocktest.New(time.Now())
+//  go func() {
+//  	fake.BlockUntil(1)
+//  	fake.Advance(time.Hour)
+//  }()
+//  strategy.WithClock(fake).Delay(time.Hour)(breaker, 0, nil)
+//
+type Clock struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	now      time.Time
+	sleepers []*timer
+}
+
+// New creates a fake clock that starts at the given moment.
+func New(now time.Time) *Clock {
+	fake := &Clock{now: now}
+	fake.cond = sync.NewCond(&fake.mu)
+	return fake
+}
+
+// Now returns the current time of the fake clock.
+func (fake *Clock) Now() time.Time {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return fake.now
+}
+
+// NewTimer creates a new Timer that fires when the fake clock
+// is advanced by at least the given duration.
+func (fake *Clock) NewTimer(duration time.Duration) clock.Timer {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	t := &timer{fake: fake, at: fake.now.Add(duration), c: make(chan time.Time, 1)}
+	if duration <= 0 {
+		t.c <- fake.now
+		return t
+	}
+	fake.sleepers = append(fake.sleepers, t)
+	fake.cond.Broadcast()
+	return t
+}
+
+// Advance moves the fake clock forward by the given duration
+// and fires all the timers that are due.
+func (fake *Clock) Advance(duration time.Duration) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	fake.now = fake.now.Add(duration)
+	sort.SliceStable(fake.sleepers, func(i, j int) bool {
+		return fake.sleepers[i].at.Before(fake.sleepers[j].at)
+	})
+	pending := fake.sleepers[:0]
+	for _, t := range fake.sleepers {
+		if t.at.After(fake.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- fake.now
+	}
+	fake.sleepers = pending
+	fake.cond.Broadcast()
+}
+
+// Sleepers returns the number of pending timers.
+func (fake *Clock) Sleepers() int {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	return len(fake.sleepers)
+}
+
+// BlockUntil blocks until the fake clock has at least the given number
+// of pending timers.
+func (fake *Clock) BlockUntil(sleepers int) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	for len(fake.sleepers) < sleepers {
+		fake.cond.Wait()
+	}
+}
+
+type timer struct {
+	fake *Clock
+	at   time.Time
+	c    chan time.Time
+}
+
+func (t *timer) C() <-chan time.Time { return t.c }
+
+func (t *timer) Stop() bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+
+	for i, sleeper := range t.fake.sleepers {
+		if sleeper == t {
+			t.fake.sleepers = append(t.fake.sleepers[:i], t.fake.sleepers[i+1:]...)
+			t.fake.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package clocktest_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/v5/clock/clocktest"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("advance", func(t *testing.T) {
+		fake := New(start)
+		first, second := fake.NewTimer(time.Second), fake.NewTimer(time.Minute)
+		if expected, obtained := 2, fake.Sleepers(); expected != obtained {
+			t.Fatalf("expected: %d, obtained: %d", expected, obtained)
+		}
+
+		fake.Advance(time.Second)
+		if expected, obtained := start.Add(time.Second), fake.Now(); !expected.Equal(obtained) {
+			t.Errorf("expected: %s, obtained: %s", expected, obtained)
+		}
+		select {
+		case now := <-first.C():
+			if !now.Equal(start.Add(time.Second)) {
+				t.Errorf("unexpected time: %s", now)
+			}
+		default:
+			t.Error("timer is not fired")
+		}
+		select {
+		case <-second.C():
+			t.Error("timer is fired too early")
+		default:
+		}
+		if expected, obtained := 1, fake.Sleepers(); expected != obtained {
+			t.Errorf("expected: %d, obtained: %d", expected, obtained)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		fake := New(start)
+		timer := fake.NewTimer(time.Second)
+		if !timer.Stop() {
+			t.Error("timer is not stopped")
+		}
+		if timer.Stop() {
+			t.Error("timer is stopped twice")
+		}
+		fake.Advance(time.Hour)
+		select {
+		case <-timer.C():
+			t.Error("stopped timer is fired")
+		default:
+		}
+	})
+
+	t.Run("non-positive duration", func(t *testing.T) {
+		fake := New(start)
+		select {
+		case <-fake.NewTimer(0).C():
+		default:
+			t.Error("timer is not fired")
+		}
+		if fake.Sleepers() != 0 {
+			t.Error("unexpected sleepers")
+		}
+	})
+
+	t.Run("block until", func(t *testing.T) {
+		fake := New(start)
+		fired := make(chan time.Time)
+		go func() {
+			fired <- <-fake.NewTimer(time.Hour).C()
+		}()
+
+		fake.BlockUntil(1)
+		fake.Advance(time.Hour)
+		if now := <-fired; !now.Equal(start.Add(time.Hour)) {
+			t.Errorf("unexpected time: %s", now)
+		}
+	})
+}
@@ -0,0 +1,296 @@
+// Command retry runs a command until it succeeds, using the same strategies
+// as the retry package.
+//
+//  retry -limit 5 -backoff exponential:100ms,2 -jitter full -timeout 30s -- curl -f https://example.com
+//
+// The output of the command is streamed as is, the interrupt and terminate
+// signals are forwarded to the running command and stop the retrying process.
+// The tool exits with the last status of the command.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/policy"
+)
+
+// Exit codes reported by the tool itself.
+const (
+	usage      = 2
+	failure    = 1
+	notStarted = 127
+)
+
+func main() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr, signals))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer, signals <-chan os.Signal) int {
+	flags := flag.NewFlagSet("retry", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: retry [flags] -- command [args...]")
+		flags.PrintDefaults()
+	}
+
+	var (
+		limit          = flags.Uint("limit", 3, "maximum number of attempts")
+		backoffSpec    = flags.String("backoff", "", "delays between attempts as kind:duration[,parameter], e.g. exponential:100ms,2")
+		maxDelay       = flags.Duration("max", 0, "maximum delay between attempts")
+		jitterSpec     = flags.String("jitter", "", "randomization of delays as kind[:parameter], e.g. full or deviation:0.25")
+		timeout        = flags.Duration("timeout", 0, "timeout for the whole retrying process")
+		attemptTimeout = flags.Duration("attempt-timeout", 0, "timeout for each attempt")
+		retryOn        = codes{}
+		stopOn         = codes{}
+	)
+	flags.Var(&retryOn, "retry-on", "comma-separated exit codes to retry, any non-zero code if omitted")
+	flags.Var(&stopOn, "stop-on", "comma-separated exit codes to stop retrying on")
+	if err := flags.Parse(args); err != nil {
+		return usage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return usage
+	}
+
+	config := policy.Policy{Attempts: *limit, Timeout: policy.Duration(*attemptTimeout)}
+	var err error
+	if config.Backoff, err = parseBackoff(*backoffSpec, *maxDelay); err != nil {
+		fmt.Fprintln(stderr, "retry:", err)
+		return usage
+	}
+	if config.Jitter, err = parseJitter(*jitterSpec); err != nil {
+		fmt.Fprintln(stderr, "retry:", err)
+		return usage
+	}
+	how, err := config.How()
+	if err != nil {
+		fmt.Fprintln(stderr, "retry:", err)
+		return usage
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if *timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	supervisor := &supervisor{cancel: cancel}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				supervisor.forward(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	status := -1
+	action := func(ctx context.Context) error {
+		cmd := exec.CommandContext(ctx, flags.Arg(0), flags.Args()[1:]...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+		if err := supervisor.start(cmd); err != nil {
+			if !errors.Is(err, errInterrupted) {
+				status = notStarted
+			}
+			return err
+		}
+		err := cmd.Wait()
+		supervisor.finish()
+		status = code(cmd.ProcessState)
+		return err
+	}
+	how = append(retry.How{
+		func(retry.Breaker, uint, error) bool { return !supervisor.interrupted() },
+		func(_ retry.Breaker, attempt uint, _ error) bool {
+			if attempt == 0 {
+				return true
+			}
+			if status == notStarted || stopOn.has(status) {
+				return false
+			}
+			return len(retryOn) == 0 || retryOn.has(status)
+		},
+	}, how...)
+
+	err = retry.Do(retry.With(ctx, config.Options()...), action, how...)
+	if err != nil && status == notStarted {
+		fmt.Fprintln(stderr, "retry:", err)
+	}
+	if status == -1 {
+		if err != nil {
+			fmt.Fprintln(stderr, "retry:", err)
+		}
+		return failure
+	}
+	return status
+}
+
+var errInterrupted = errors.New("interrupted")
+
+// supervisor tracks the running command to forward signals to it.
+type supervisor struct {
+	mu      sync.Mutex
+	process *os.Process
+	stopped bool
+	cancel  context.CancelFunc
+}
+
+func (s *supervisor) start(cmd *exec.Cmd) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return errInterrupted
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	s.process = cmd.Process
+	return nil
+}
+
+func (s *supervisor) finish() {
+	s.mu.Lock()
+	s.process = nil
+	s.mu.Unlock()
+}
+
+func (s *supervisor) forward(sig os.Signal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	if s.process != nil {
+		_ = s.process.Signal(sig)
+		return
+	}
+	s.cancel()
+}
+
+func (s *supervisor) interrupted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+// codes is a flag.Value that holds a set of exit codes.
+type codes map[int]struct{}
+
+func (set codes) String() string {
+	list := make([]string, 0, len(set))
+	for code := range set {
+		list = append(list, strconv.Itoa(code))
+	}
+	return strings.Join(list, ",")
+}
+
+func (set codes) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return fmt.Errorf("invalid exit code %q", item)
+		}
+		set[code] = struct{}{}
+	}
+	return nil
+}
+
+func (set codes) has(code int) bool {
+	_, is := set[code]
+	return is
+}
+
+func code(state *os.ProcessState) int {
+	if status := state.ExitCode(); status >= 0 {
+		return status
+	}
+	if ws, is := state.Sys().(interface {
+		Signaled() bool
+		Signal() syscall.Signal
+	}); is && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return failure
+}
+
+func parseBackoff(spec string, max time.Duration) (*policy.Backoff, error) {
+	if spec == "" {
+		if max != 0 {
+			return nil, errors.New("-max requires -backoff")
+		}
+		return nil, nil
+	}
+	kind, params, _ := strings.Cut(spec, ":")
+	config := &policy.Backoff{Kind: kind, Max: policy.Duration(max)}
+	values := strings.Split(params, ",")
+	duration, err := time.ParseDuration(values[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid backoff %q: %w", spec, err)
+	}
+	config.Duration = policy.Duration(duration)
+	if len(values) > 2 || len(values) == 2 && kind != "incremental" && kind != "exponential" {
+		return nil, fmt.Errorf("invalid backoff %q: unexpected parameters", spec)
+	}
+	if len(values) == 2 {
+		switch kind {
+		case "incremental":
+			increment, err := time.ParseDuration(values[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid backoff %q: %w", spec, err)
+			}
+			config.Increment = policy.Duration(increment)
+		case "exponential":
+			if config.Base, err = strconv.ParseFloat(values[1], 64); err != nil {
+				return nil, fmt.Errorf("invalid backoff %q: %w", spec, err)
+			}
+		}
+	}
+	return config, nil
+}
+
+func parseJitter(spec string) (*policy.Jitter, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	kind, param, has := strings.Cut(spec, ":")
+	config := &policy.Jitter{Kind: kind}
+	if !has {
+		return config, nil
+	}
+	switch kind {
+	case "deviation":
+		factor, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jitter %q: %w", spec, err)
+		}
+		config.Factor = factor
+	case "normal":
+		deviation, err := time.ParseDuration(param)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jitter %q: %w", spec, err)
+		}
+		config.Deviation = policy.Duration(deviation)
+	default:
+		return nil, fmt.Errorf("invalid jitter %q: unexpected parameter", spec)
+	}
+	return config, nil
+}
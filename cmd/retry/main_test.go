@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	tests := map[string]struct {
+		args     []string
+		script   string
+		status   int
+		attempts int
+	}{
+		"success": {
+			script:   "exit 0",
+			status:   0,
+			attempts: 1,
+		},
+		"eventual success": {
+			args:     []string{"-limit", "5", "-backoff", "constant:1ms"},
+			script:   `[ "$(wc -l < "$COUNTER")" -ge 3 ]`,
+			status:   0,
+			attempts: 3,
+		},
+		"exhausted": {
+			args:     []string{"-limit", "3", "-backoff", "exponential:1ms,2", "-jitter", "full"},
+			script:   "exit 3",
+			status:   3,
+			attempts: 3,
+		},
+		"retry on": {
+			args:     []string{"-retry-on", "75"},
+			script:   "exit 4",
+			status:   4,
+			attempts: 1,
+		},
+		"stop on": {
+			args:     []string{"-limit", "5", "-stop-on", "2,4"},
+			script:   "exit 4",
+			status:   4,
+			attempts: 1,
+		},
+		"attempt timeout": {
+			args:     []string{"-limit", "2", "-attempt-timeout", "50ms"},
+			script:   "exec sleep 5",
+			status:   128 + int(syscall.SIGKILL),
+			attempts: 2,
+		},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			counter := filepath.Join(t.TempDir(), "counter")
+			t.Setenv("COUNTER", counter)
+
+			script := `echo >> "$COUNTER"; echo out; echo err >&2; ` + tc.script
+			args := append(append([]string{}, tc.args...), "--", "sh", "-c", script)
+
+			var stdout, stderr bytes.Buffer
+			status := run(args, nil, &stdout, &stderr, nil)
+			if status != tc.status {
+				t.Errorf("expected: %d, obtained: %d", tc.status, status)
+			}
+			if obtained := attempts(t, counter); obtained != tc.attempts {
+				t.Errorf("expected: %d, obtained: %d", tc.attempts, obtained)
+			}
+			if expected := strings.Repeat("out\n", tc.attempts); stdout.String() != expected {
+				t.Errorf("expected: %q, obtained: %q", expected, stdout.String())
+			}
+			if expected := strings.Repeat("err\n", tc.attempts); stderr.String() != expected {
+				t.Errorf("expected: %q, obtained: %q", expected, stderr.String())
+			}
+		})
+	}
+
+	t.Run("not started", func(t *testing.T) {
+		var stderr bytes.Buffer
+		status := run([]string{"-limit", "3", "--", filepath.Join(t.TempDir(), "missing")}, nil, nil, &stderr, nil)
+		if status != notStarted || stderr.Len() == 0 {
+			t.Errorf("unexpected result: %d, %q", status, stderr.String())
+		}
+	})
+
+	t.Run("signal", func(t *testing.T) {
+		dir := t.TempDir()
+		started, counter := filepath.Join(dir, "started"), filepath.Join(dir, "counter")
+		t.Setenv("COUNTER", counter)
+
+		signals := make(chan os.Signal, 1)
+		go func() {
+			for {
+				if _, err := os.Stat(started); err == nil {
+					signals <- syscall.SIGTERM
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}()
+
+		script := `echo >> "$COUNTER"; trap 'exit 7' TERM; touch "` + started + `"; while :; do sleep 0.01; done`
+		status := run([]string{"-limit", "5", "--", "sh", "-c", script}, nil, nil, nil, signals)
+		if status != 7 {
+			t.Errorf("expected: %d, obtained: %d", 7, status)
+		}
+		if obtained := attempts(t, counter); obtained != 1 {
+			t.Errorf("expected: %d, obtained: %d", 1, obtained)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		start := time.Now()
+		status := run([]string{"-limit", "100", "-backoff", "constant:1h", "-timeout", "50ms", "--", "sh", "-c", "exit 5"},
+			nil, nil, nil, nil)
+		if status != 5 {
+			t.Errorf("expected: %d, obtained: %d", 5, status)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("unexpected elapsed time: %s", elapsed)
+		}
+	})
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := map[string][]string{
+		"no command":           {"-limit", "3"},
+		"unknown flag":         {"-retries", "3", "--", "true"},
+		"invalid code":         {"-retry-on", "x", "--", "true"},
+		"no attempts":          {"-limit", "0", "--", "true"},
+		"unknown backoff":      {"-backoff", "random:1s", "--", "true"},
+		"invalid duration":     {"-backoff", "linear:soon", "--", "true"},
+		"unexpected parameter": {"-backoff", "linear:1s,2", "--", "true"},
+		"invalid base":         {"-backoff", "exponential:1s,x", "--", "true"},
+		"max without backoff":  {"-max", "1s", "--", "true"},
+		"unknown jitter":       {"-backoff", "linear:1s", "-jitter", "chaos", "--", "true"},
+		"invalid factor":       {"-backoff", "linear:1s", "-jitter", "deviation:x", "--", "true"},
+	}
+	for name, test := range tests {
+		args := test
+		t.Run(name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			if status := run(args, nil, nil, &stderr, nil); status != usage {
+				t.Errorf("expected: %d, obtained: %d", usage, status)
+			}
+			if stderr.Len() == 0 {
+				t.Error("usage error expected")
+			}
+		})
+	}
+}
+
+func TestParseBackoff(t *testing.T) {
+	config, err := parseBackoff("incremental:1s,100ms", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Kind != "incremental" || config.Duration.String() != "1s" ||
+		config.Increment.String() != "100ms" || config.Max.String() != "1m0s" {
+		t.Errorf("unexpected config: %+v", config)
+	}
+
+	config, err = parseBackoff("exponential:100ms,1.5", 0)
+	if err != nil || config.Base != 1.5 {
+		t.Errorf("unexpected result: %+v, %v", config, err)
+	}
+}
+
+// helpers
+
+func attempts(t *testing.T, counter string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return bytes.Count(data, []byte("\n"))
+}
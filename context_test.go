@@ -112,15 +112,6 @@ func TestConvert(t *testing.T) {
 
 // helpers
 
-func stop(timer *time.Timer) {
-	if !timer.Stop() {
-		select {
-		case <-timer.C:
-		default:
-		}
-	}
-}
-
 func verify(t *testing.T, ctx context.Context, cancel context.CancelFunc, sig chan struct{}) {
 	t.Helper()
 
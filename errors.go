@@ -1,5 +1,10 @@
 package retry
 
+import (
+	"errors"
+	"fmt"
+)
+
 const internal Error = "have no any try"
 
 // Error defines a string-based error without a different root cause.
@@ -37,3 +42,66 @@ type causer interface {
 type wrapper interface {
 	Unwrap() error
 }
+
+const (
+	// ErrExhausted is a sentinel to check that strategies
+	// have halted the retry process, see ExhaustedError.
+	ErrExhausted Error = "retry: attempts exhausted"
+	// ErrCancelled is a sentinel to check that the breaker
+	// has interrupted the retry process, see CancelledError.
+	ErrCancelled Error = "retry: cancelled"
+)
+
+// ExhaustedError is returned when strategies halt the retry process.
+type ExhaustedError struct {
+	// Attempts is the number of made attempts.
+	Attempts uint
+	// Err is the error returned by the last attempt.
+	Err error
+}
+
+// Error returns a string representation of an error.
+func (err *ExhaustedError) Error() string {
+	return fmt.Sprintf("retry: gave up after %d attempts: %v", err.Attempts, err.Err)
+}
+
+// Unwrap returns the error returned by the last attempt.
+func (err *ExhaustedError) Unwrap() error { return err.Err }
+
+// Is reports whether the target is the ErrExhausted.
+func (err *ExhaustedError) Is(target error) bool { return target == ErrExhausted }
+
+// CancelledError is returned when the breaker interrupts the retry process.
+type CancelledError struct {
+	// Attempts is the number of made attempts.
+	Attempts uint
+	// Cause is the error returned by the breaker.
+	Cause error
+	// Err is the error returned by the last attempt.
+	// It is nil if there were no attempts.
+	Err error
+}
+
+// Error returns a string representation of an error.
+func (err *CancelledError) Error() string {
+	if err.Err == nil {
+		return fmt.Sprintf("retry: cancelled after %d attempts: %v", err.Attempts, err.Cause)
+	}
+	return fmt.Sprintf("retry: cancelled after %d attempts: %v: %v", err.Attempts, err.Cause, err.Err)
+}
+
+// Unwrap returns the error returned by the breaker.
+func (err *CancelledError) Unwrap() error { return err.Cause }
+
+// Is reports whether the target is the ErrCancelled
+// or matches the error returned by the last attempt.
+func (err *CancelledError) Is(target error) bool {
+	return target == ErrCancelled || err.Err != nil && errors.Is(err.Err, target)
+}
+
+// As finds the first error in the error returned by the last attempt
+// that matches the target, and if one is found, sets the target
+// to that error value and returns true.
+func (err *CancelledError) As(target interface{}) bool {
+	return err.Err != nil && errors.As(err.Err, target)
+}
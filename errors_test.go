@@ -1,6 +1,7 @@
 package retry
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
@@ -16,6 +17,55 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestExhaustedError(t *testing.T) {
+	root := errors.New("root")
+	err := error(&ExhaustedError{Attempts: 3, Err: layer{root}})
+
+	if expected, obtained := "retry: gave up after 3 attempts: root", err.Error(); expected != obtained {
+		t.Errorf("expected: %q, obtained: %q", expected, obtained)
+	}
+	if !errors.Is(err, ErrExhausted) || errors.Is(err, ErrCancelled) {
+		t.Error("unexpected behavior")
+	}
+	if !errors.Is(err, root) {
+		t.Error("unexpected behavior")
+	}
+
+	var target layer
+	if !errors.As(err, &target) {
+		t.Error("unexpected behavior")
+	}
+}
+
+func TestCancelledError(t *testing.T) {
+	root := errors.New("root")
+	err := error(&CancelledError{Attempts: 2, Cause: context.DeadlineExceeded, Err: layer{root}})
+
+	if expected, obtained := "retry: cancelled after 2 attempts: context deadline exceeded: root",
+		err.Error(); expected != obtained {
+		t.Errorf("expected: %q, obtained: %q", expected, obtained)
+	}
+	if !errors.Is(err, ErrCancelled) || errors.Is(err, ErrExhausted) {
+		t.Error("unexpected behavior")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, root) {
+		t.Error("unexpected behavior")
+	}
+
+	var target layer
+	if !errors.As(err, &target) {
+		t.Error("unexpected behavior")
+	}
+
+	err = &CancelledError{Cause: context.Canceled}
+	if expected, obtained := "retry: cancelled after 0 attempts: context canceled", err.Error(); expected != obtained {
+		t.Errorf("expected: %q, obtained: %q", expected, obtained)
+	}
+	if !errors.Is(err, context.Canceled) || errors.Is(err, root) || errors.As(err, &target) {
+		t.Error("unexpected behavior")
+	}
+}
+
 func TestUnwrap(t *testing.T) {
 	root := errors.New("root")
 	core := unwrap(cause{layer{root}})
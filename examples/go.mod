@@ -1,6 +1,6 @@
 module github.com/kamilsk/retry/examples
 
-go 1.13
+go 1.18
 
 require github.com/kamilsk/retry/v5 v5.0.0-rc8
 
@@ -1 +1,48 @@
 package examples_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/backoff"
+	"github.com/kamilsk/retry/v5/strategy"
+	"github.com/kamilsk/retry/v5/transport"
+)
+
+// The example shows how to retry HTTP requests transparently.
+func Example_httpTransport() {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			rw.Header().Set("Retry-After", "0")
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(rw, "success communication")
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &transport.Transport{
+			How: retry.How{
+				strategy.Limit(5),
+				strategy.RetryAfter(backoff.Exponential(time.Millisecond, 2), time.Second),
+			},
+		},
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		panic(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	fmt.Println(string(body), "after", calls, "attempts")
+	// Output: success communication after 3 attempts
+}
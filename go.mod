@@ -1,3 +1,3 @@
 module github.com/kamilsk/retry/v5
 
-go 1.11
+go 1.18
@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Hedge takes the action and performs it, launching an additional copy
+// of the action each time the previous ones haven't completed after
+// a hedge delay, until one of them is successful. The other copies
+// are cancelled through the context passed to them.
+//
+// The copies parameter limits the total number of copies, including
+// the first one, and the concurrency parameter limits the number of copies
+// running at the same time, it is at least one. The delay is calculated
+// by the given backoff.Algorithm for each copy after the first.
+// If a copy fails, the next one is launched immediately.
+//
+//  err := retry.Hedge(ctx, action, 5, 2, backoff.Constant(50*time.Millisecond))
+//
+// If all copies fail, Hedge returns the ExhaustedError.
+// If the breaker interrupts the process, Hedge returns the CancelledError.
+// Options passed through the breaker are not applied.
+func Hedge(
+	breaker Breaker,
+	action func(context.Context) error,
+	copies, concurrency uint,
+	delay func(attempt uint) time.Duration,
+) error {
+	_, err := HedgeValue(breaker, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, action(ctx)
+	}, copies, concurrency, delay)
+	return err
+}
+
+// HedgeValue takes the action and performs it the same way as Hedge.
+// It returns the value produced by the first successful copy.
+func HedgeValue[T any](
+	breaker Breaker,
+	action func(context.Context) (T, error),
+	copies, concurrency uint,
+	delay func(attempt uint) time.Duration,
+) (T, error) {
+	type outcome struct {
+		value T
+		err   error
+	}
+
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	var (
+		zero     T
+		last     error = internal
+		failed   uint
+		launched uint
+		running  uint
+		next     <-chan time.Time
+		timer    *time.Timer
+		start    = time.Now()
+		vars     = new(scope)
+		done     = make(chan outcome)
+	)
+	ctx, cancel := context.WithCancel(convert(breaker))
+	defer cancel()
+
+	launch := func() {
+		info := attach(ctx, Attempt{Number: launched, Start: start}, vars)
+		go func() {
+			var result outcome
+			defer func() {
+				if r := recover(); r != nil {
+					result = outcome{err: recovered(r)}
+				}
+				// copies completed after the return are dropped
+				select {
+				case done <- result:
+				case <-ctx.Done():
+				}
+			}()
+			result.value, result.err = action(info)
+		}()
+		launched++
+		running++
+
+		if timer != nil {
+			stop(timer)
+			timer, next = nil, nil
+		}
+		if launched < copies && running < concurrency {
+			timer = time.NewTimer(delay(launched))
+			next = timer.C
+		}
+	}
+	defer func() {
+		if timer != nil {
+			stop(timer)
+		}
+	}()
+
+	if copies == 0 {
+		return zero, &ExhaustedError{Err: last}
+	}
+	launch()
+
+	for {
+		select {
+		case <-breaker.Done():
+			if failed == 0 {
+				return zero, &CancelledError{Cause: breaker.Err()}
+			}
+			return zero, &CancelledError{Attempts: failed, Cause: breaker.Err(), Err: last}
+		case result := <-done:
+			running--
+			if result.err == nil {
+				return result.value, nil
+			}
+			last = result.err
+			failed++
+			if launched < copies {
+				launch()
+				continue
+			}
+			if running == 0 {
+				return zero, &ExhaustedError{Attempts: failed, Err: last}
+			}
+		case <-next:
+			timer = nil
+			launch()
+		}
+	}
+}
+
+func stop(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/v5"
+)
+
+func TestHedge(t *testing.T) {
+	constant := func(duration time.Duration) func(uint) time.Duration {
+		return func(uint) time.Duration { return duration }
+	}
+
+	t.Run("first success wins", func(t *testing.T) {
+		cancelled := make(chan struct{})
+		action := func(ctx context.Context) (uint, error) {
+			info, _ := Info(ctx)
+			if info.Number == 0 {
+				<-ctx.Done()
+				close(cancelled)
+				return 0, ctx.Err()
+			}
+			return info.Number, nil
+		}
+
+		value, err := HedgeValue(breaker(), action, 3, 3, constant(time.Millisecond))
+		if err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if value != 1 {
+			t.Errorf("expected: %d, obtained: %d", 1, value)
+		}
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Error("the slow copy is not cancelled")
+		}
+	})
+
+	t.Run("failure launches next copy", func(t *testing.T) {
+		var calls int32
+		action := func(ctx context.Context) error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				return errors.New("failure")
+			}
+			return nil
+		}
+		if err := Hedge(breaker(), action, 2, 2, constant(time.Hour)); err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if calls != 2 {
+			t.Errorf("expected: %d, obtained: %d", 2, calls)
+		}
+	})
+
+	t.Run("all copies fail", func(t *testing.T) {
+		var calls int32
+		action := func(context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(time.Millisecond)
+			return Error("failure")
+		}
+		err := Hedge(breaker(), action, 3, 3, constant(0))
+		if !reflect.DeepEqual(&ExhaustedError{Attempts: 3, Err: Error("failure")}, err) {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if calls != 3 {
+			t.Errorf("expected: %d, obtained: %d", 3, calls)
+		}
+	})
+
+	t.Run("limited concurrency", func(t *testing.T) {
+		var calls, running, peak int32
+		action := func(context.Context) error {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				observed := atomic.LoadInt32(&peak)
+				if current <= observed || atomic.CompareAndSwapInt32(&peak, observed, current) {
+					break
+				}
+			}
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(5 * time.Millisecond)
+			return Error("failure")
+		}
+		err := Hedge(breaker(), action, 6, 2, constant(0))
+		if !reflect.DeepEqual(&ExhaustedError{Attempts: 6, Err: Error("failure")}, err) {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if calls != 6 || peak != 2 {
+			t.Errorf("unexpected result: %d calls, %d at most concurrently", calls, peak)
+		}
+	})
+
+	t.Run("unlimited copies", func(t *testing.T) {
+		var calls int32
+		action := func(context.Context) error {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return Error("failure")
+			}
+			return nil
+		}
+		if err := Hedge(breaker(), action, math.MaxUint, 1, constant(time.Hour)); err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if calls != 3 {
+			t.Errorf("expected: %d, obtained: %d", 3, calls)
+		}
+	})
+
+	t.Run("no copies", func(t *testing.T) {
+		err := Hedge(breaker(), func(context.Context) error { return nil }, 0, 1, constant(0))
+		if !reflect.DeepEqual(&ExhaustedError{Err: Error("have no any try")}, err) {
+			t.Errorf("unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("breaker cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Millisecond)
+		defer cancel()
+
+		action := func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		err := Hedge(ctx, action, 2, 2, constant(time.Hour))
+		if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("panic recovery", func(t *testing.T) {
+		tests := map[string]struct {
+			panic    interface{}
+			expected error
+		}{
+			"error panic":     {Error("failure"), Error("failure")},
+			"non-error panic": {"non-error", fmt.Errorf("retry: unexpected panic: %#v", "non-error")},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				err := Hedge(breaker(), func(context.Context) error { panic(test.panic) }, 1, 1, constant(0))
+				if !reflect.DeepEqual(&ExhaustedError{Attempts: 1, Err: test.expected}, err) {
+					t.Errorf("unexpected error: %#v", err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package retry
+
+// Hook observes the retry process lifecycle events.
+//
+// Before an attempt the Attempt describes the previous one, see Info.
+// After an attempt its own start time and duration are in the Previous
+// and Duration fields, and its error is in the Err and Cause fields.
+type Hook interface {
+	// OnAttemptStart is called before the action is performed.
+	OnAttemptStart(Attempt)
+	// OnAttemptError is called after the action is failed.
+	OnAttemptError(Attempt, error)
+	// OnSuccess is called after the action is succeeded.
+	OnSuccess(Attempt)
+	// OnGiveUp is called when strategies halt the retry process.
+	// The error is the one that the retry process returns.
+	OnGiveUp(Attempt, error)
+	// OnBreakerCancel is called when the breaker interrupts the retry process.
+	// The error is the one that the breaker returns.
+	OnBreakerCancel(Attempt, error)
+}
+
+// Hooks is an adapter to use ordinary functions as a Hook.
+// Nil functions are skipped.
+type Hooks struct {
+	AttemptStart  func(Attempt)
+	AttemptError  func(Attempt, error)
+	Success       func(Attempt)
+	GiveUp        func(Attempt, error)
+	BreakerCancel func(Attempt, error)
+}
+
+// OnAttemptStart calls the AttemptStart if it is defined.
+func (hook Hooks) OnAttemptStart(attempt Attempt) {
+	if hook.AttemptStart != nil {
+		hook.AttemptStart(attempt)
+	}
+}
+
+// OnAttemptError calls the AttemptError if it is defined.
+func (hook Hooks) OnAttemptError(attempt Attempt, err error) {
+	if hook.AttemptError != nil {
+		hook.AttemptError(attempt, err)
+	}
+}
+
+// OnSuccess calls the Success if it is defined.
+func (hook Hooks) OnSuccess(attempt Attempt) {
+	if hook.Success != nil {
+		hook.Success(attempt)
+	}
+}
+
+// OnGiveUp calls the GiveUp if it is defined.
+func (hook Hooks) OnGiveUp(attempt Attempt, err error) {
+	if hook.GiveUp != nil {
+		hook.GiveUp(attempt, err)
+	}
+}
+
+// OnBreakerCancel calls the BreakerCancel if it is defined.
+func (hook Hooks) OnBreakerCancel(attempt Attempt, err error) {
+	if hook.BreakerCancel != nil {
+		hook.BreakerCancel(attempt, err)
+	}
+}
+
+type hooks []Hook
+
+func (list hooks) OnAttemptStart(attempt Attempt) {
+	for _, hook := range list {
+		hook.OnAttemptStart(attempt)
+	}
+}
+
+func (list hooks) OnAttemptError(attempt Attempt, err error) {
+	for _, hook := range list {
+		hook.OnAttemptError(attempt, err)
+	}
+}
+
+func (list hooks) OnSuccess(attempt Attempt) {
+	for _, hook := range list {
+		hook.OnSuccess(attempt)
+	}
+}
+
+func (list hooks) OnGiveUp(attempt Attempt, err error) {
+	for _, hook := range list {
+		hook.OnGiveUp(attempt, err)
+	}
+}
+
+func (list hooks) OnBreakerCancel(attempt Attempt, err error) {
+	for _, hook := range list {
+		hook.OnBreakerCancel(attempt, err)
+	}
+}
@@ -0,0 +1,151 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	. "github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestHook(t *testing.T) {
+	failure := errors.New("failure")
+
+	tests := map[string]struct {
+		breaker    Breaker
+		strategies How
+		action     func(context.Context) error
+		expected   []string
+	}{
+		"success at first": {
+			breaker(),
+			How{strategy.Limit(3)},
+			func(context.Context) error { return nil },
+			[]string{"start 0", "success 0 <nil>"},
+		},
+		"success after failure": {
+			breaker(),
+			How{strategy.Limit(3)},
+			sequence(failure, nil),
+			[]string{"start 0", "error 0 failure", "start 1 failure", "success 1 <nil>"},
+		},
+		"give up": {
+			breaker(),
+			How{strategy.Limit(2)},
+			sequence(failure, failure),
+			[]string{"start 0", "error 0 failure", "start 1 failure", "error 1 failure", "give up 2 retry: gave up after 2 attempts: failure"},
+		},
+		"give up without attempts": {
+			breaker(),
+			How{strategy.Limit(0)},
+			sequence(),
+			[]string{"give up 0 retry: gave up after 0 attempts: have no any try"},
+		},
+		"breaker cancel": {
+			interrupted(),
+			nil,
+			sequence(),
+			[]string{"cancel 0 context canceled"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var events []string
+			hook := Hooks{
+				AttemptStart: func(attempt Attempt) {
+					if attempt.Err == nil {
+						events = append(events, fmt.Sprintf("start %d", attempt.Number))
+						return
+					}
+					events = append(events, fmt.Sprintf("start %d %v", attempt.Number, attempt.Err))
+				},
+				AttemptError: func(attempt Attempt, err error) {
+					events = append(events, fmt.Sprintf("error %d %v", attempt.Number, err))
+				},
+				Success: func(attempt Attempt) {
+					events = append(events, fmt.Sprintf("success %d %v", attempt.Number, attempt.Err))
+				},
+				GiveUp: func(attempt Attempt, err error) {
+					events = append(events, fmt.Sprintf("give up %d %v", attempt.Number, err))
+				},
+				BreakerCancel: func(attempt Attempt, err error) {
+					events = append(events, fmt.Sprintf("cancel %d %v", attempt.Number, err))
+				},
+			}
+
+			_ = Do(With(test.breaker, Observe(hook)), test.action, test.strategies...)
+			if !reflect.DeepEqual(test.expected, events) {
+				t.Errorf("expected: %q, obtained: %q", test.expected, events)
+			}
+		})
+	}
+
+	t.Run("breaker cancel in goroutine", func(t *testing.T) {
+		var events []string
+		hook := Hooks{
+			AttemptStart: func(attempt Attempt) {
+				events = append(events, fmt.Sprintf("start %d", attempt.Number))
+			},
+			AttemptError: func(attempt Attempt, err error) {
+				events = append(events, fmt.Sprintf("error %d %v", attempt.Number, err))
+			},
+			Success: func(attempt Attempt) {
+				events = append(events, fmt.Sprintf("success %d", attempt.Number))
+			},
+			BreakerCancel: func(attempt Attempt, err error) {
+				events = append(events, fmt.Sprintf("cancel %d %v", attempt.Number, err))
+			},
+		}
+		ctx, cancel := context.WithCancel(context.TODO())
+		release, orphaned := make(chan struct{}), make(chan struct{})
+		action := func(context.Context) error {
+			cancel()
+			<-release
+			return failure
+		}
+		observe := func(_ Breaker, attempt uint, _ error) bool {
+			if attempt > 0 {
+				close(orphaned)
+			}
+			return true
+		}
+
+		err := Go(With(ctx, Observe(hook)), action, observe)
+		if !errors.Is(err, ErrCancelled) {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		close(release)
+		<-orphaned
+
+		expected := []string{"start 0", "cancel 1 context canceled"}
+		if !reflect.DeepEqual(expected, events) {
+			t.Errorf("expected: %q, obtained: %q", expected, events)
+		}
+	})
+
+	t.Run("nil functions", func(t *testing.T) {
+		hook := Hooks{}
+		ctx := With(breaker(), Observe(hook))
+		if err := Do(ctx, sequence(failure, nil), strategy.Limit(2)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := Do(ctx, sequence(failure), strategy.Limit(1)); !errors.Is(err, failure) {
+			t.Errorf("expected: %#v, obtained: %#v", failure, err)
+		}
+	})
+}
+
+// helpers
+
+func sequence(errs ...error) func(context.Context) error {
+	var i int
+	return func(context.Context) error {
+		err := errs[i]
+		i++
+		return err
+	}
+}
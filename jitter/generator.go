@@ -0,0 +1,72 @@
+package jitter
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Generator defines a source of random numbers used by Transformations.
+// The *rand.Rand satisfies it, but is not safe for concurrent use,
+// so wrap it by Locked or use Pooled if a Transformation is shared
+// between goroutines.
+type Generator interface {
+	// Int63n returns a non-negative pseudo-random number in [0, n).
+	// It panics if n <= 0.
+	Int63n(n int64) int64
+	// NormFloat64 returns a normally distributed float64
+	// with standard normal distribution.
+	NormFloat64() float64
+}
+
+// Locked returns a Generator that serializes access
+// to the given generator by a mutex.
+func Locked(generator Generator) Generator {
+	return &locked{generator: generator}
+}
+
+// Pooled returns a Generator that keeps a pool of *rand.Rand
+// created from sources returned by the given function,
+// so concurrent goroutines don't contend on a single mutex.
+//
+//  generator := jitter.Pooled(func() rand.Source {
+//  	return rand.NewSource(time.Now().UnixNano())
+//  })
+//
+func Pooled(source func() rand.Source) Generator {
+	return &pooled{pool: sync.Pool{New: func() interface{} {
+		return rand.New(source())
+	}}}
+}
+
+type locked struct {
+	mu        sync.Mutex
+	generator Generator
+}
+
+func (generator *locked) Int63n(n int64) int64 {
+	generator.mu.Lock()
+	defer generator.mu.Unlock()
+	return generator.generator.Int63n(n)
+}
+
+func (generator *locked) NormFloat64() float64 {
+	generator.mu.Lock()
+	defer generator.mu.Unlock()
+	return generator.generator.NormFloat64()
+}
+
+type pooled struct {
+	pool sync.Pool
+}
+
+func (generator *pooled) Int63n(n int64) int64 {
+	rnd := generator.pool.Get().(*rand.Rand)
+	defer generator.pool.Put(rnd)
+	return rnd.Int63n(n)
+}
+
+func (generator *pooled) NormFloat64() float64 {
+	rnd := generator.pool.Get().(*rand.Rand)
+	defer generator.pool.Put(rnd)
+	return rnd.NormFloat64()
+}
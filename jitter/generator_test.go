@@ -0,0 +1,62 @@
+package jitter_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/v5/jitter"
+)
+
+func TestLocked(t *testing.T) {
+	const seed = 0
+	const duration = time.Millisecond
+
+	transformation := Full(Locked(rand.New(rand.NewSource(seed))))
+
+	// Based on constant seed
+	expectedDurations := []time.Duration{165505, 393152, 995827, 197794, 376202}
+
+	for _, expected := range expectedDurations {
+		result := transformation(duration)
+
+		if result != expected {
+			t.Errorf("transformation expected to return a %s duration, but received %s instead", expected, result)
+		}
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	const duration = time.Millisecond
+
+	tests := map[string]Generator{
+		"locked": Locked(rand.New(rand.NewSource(0))),
+		"pooled": Pooled(func() rand.Source { return rand.NewSource(0) }),
+	}
+	for name, generator := range tests {
+		generator := generator
+		t.Run(name, func(t *testing.T) {
+			transformations := []Transformation{
+				Full(generator),
+				Equal(generator),
+				Deviation(generator, 0.5),
+				NormalDistribution(generator, float64(duration/2)),
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 1000; j++ {
+						for _, transformation := range transformations {
+							_ = transformation(duration)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
@@ -3,7 +3,6 @@ package jitter
 
 import (
 	"math"
-	"math/rand"
 	"time"
 )
 
@@ -13,49 +12,87 @@ type Transformation = func(duration time.Duration) time.Duration
 
 // Full creates a Transformation that transforms a duration into a result
 // duration in [0, n) randomly, where n is the given duration.
+// Non-positive durations are transformed into zero.
 //
 // The given generator is what is used to determine the random transformation.
 //
 // Inspired by https://www.awsarchitectureblog.com/2015/03/backoff.html
-func Full(generator *rand.Rand) Transformation {
+func Full(generator Generator) Transformation {
 	return func(duration time.Duration) time.Duration {
+		if duration <= 0 {
+			return 0
+		}
 		return time.Duration(generator.Int63n(int64(duration)))
 	}
 }
 
 // Equal creates a Transformation that transforms a duration into a result
 // duration in [n/2, n) randomly, where n is the given duration.
+// Non-positive durations are transformed into zero.
 //
 // The given generator is what is used to determine the random transformation.
 //
 // Inspired by https://www.awsarchitectureblog.com/2015/03/backoff.html
-func Equal(generator *rand.Rand) Transformation {
+func Equal(generator Generator) Transformation {
 	return func(duration time.Duration) time.Duration {
+		if duration <= 0 {
+			return 0
+		}
 		return (duration / 2) + time.Duration(generator.Int63n(int64(duration))/2)
 	}
 }
 
 // Deviation creates a Transformation that transforms a duration into a result
 // duration that deviates from the input randomly by a given factor.
+// Non-positive durations are transformed into zero, and the result
+// is clamped to be non-negative.
 //
 // The given generator is what is used to determine the random transformation.
 //
 // Inspired by https://developers.google.com/api-client-library/java/google-http-java-client/backoff
-func Deviation(generator *rand.Rand, factor float64) Transformation {
+func Deviation(generator Generator, factor float64) Transformation {
+	factor = math.Abs(factor)
 	return func(duration time.Duration) time.Duration {
-		min := int64(math.Floor(float64(duration) * (1 - factor)))
-		max := int64(math.Ceil(float64(duration) * (1 + factor)))
-		return time.Duration(generator.Int63n(max-min) + min)
+		if duration <= 0 {
+			return 0
+		}
+		if math.IsNaN(factor) {
+			return duration
+		}
+		min := clamp(math.Floor(float64(duration) * (1 - factor)))
+		max := clamp(math.Ceil(float64(duration) * (1 + factor)))
+		if max <= min {
+			return min
+		}
+		return min + time.Duration(generator.Int63n(int64(max-min)))
 	}
 }
 
 // NormalDistribution creates a Transformation that transforms a duration into a
 // result duration based on a normal distribution of the input and the given
-// standard deviation.
+// standard deviation. Non-positive durations are transformed into zero,
+// and the result is clamped to be non-negative.
 //
 // The given generator is what is used to determine the random transformation.
-func NormalDistribution(generator *rand.Rand, standardDeviation float64) Transformation {
+func NormalDistribution(generator Generator, standardDeviation float64) Transformation {
 	return func(duration time.Duration) time.Duration {
-		return time.Duration(generator.NormFloat64()*standardDeviation + float64(duration))
+		if duration <= 0 {
+			return 0
+		}
+		result := generator.NormFloat64()*standardDeviation + float64(duration)
+		if math.IsNaN(result) {
+			return duration
+		}
+		return clamp(result)
 	}
 }
+
+func clamp(duration float64) time.Duration {
+	if duration <= 0 {
+		return 0
+	}
+	if duration >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(duration)
+}
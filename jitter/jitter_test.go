@@ -1,10 +1,12 @@
 package jitter_test
 
 import (
+	"math"
 	"math/rand"
 	"testing"
 	"time"
 
+	"github.com/kamilsk/retry/v5/backoff"
 	. "github.com/kamilsk/retry/v5/jitter"
 )
 
@@ -89,3 +91,75 @@ func TestNormalDistribution(t *testing.T) {
 		}
 	}
 }
+
+func TestNonPositiveDurations(t *testing.T) {
+	for name, transformation := range transformations(rand.New(rand.NewSource(0))) {
+		for _, duration := range []time.Duration{0, -1, -time.Second, math.MinInt64} {
+			if result := transformation(duration); result != 0 {
+				t.Errorf("%s: transformation expected to return zero for %s, but received %s instead", name, duration, result)
+			}
+		}
+	}
+}
+
+func TestComposition(t *testing.T) {
+	algorithms := map[string]backoff.Algorithm{
+		"constant zero":      backoff.Constant(0),
+		"constant negative":  backoff.Constant(-time.Second),
+		"incremental":        backoff.Incremental(-time.Second, time.Millisecond),
+		"linear":             backoff.Linear(time.Millisecond),
+		"exponential":        backoff.Exponential(time.Millisecond, 3),
+		"binary exponential": backoff.BinaryExponential(time.Millisecond),
+		"fibonacci":          backoff.Fibonacci(time.Millisecond),
+		"cap":                backoff.Cap(backoff.BinaryExponential(time.Millisecond), time.Second),
+		"decorrelated":       backoff.DecorrelatedJitter(0, time.Second, rand.New(rand.NewSource(0)))(),
+	}
+	attempts := []uint{0, 1, 2, 10, 63, 64, 100, math.MaxUint32}
+
+	for name, transformation := range transformations(rand.New(rand.NewSource(0))) {
+		for algorithm, calculate := range algorithms {
+			for _, attempt := range attempts {
+				if result := transformation(calculate(attempt)); result < 0 {
+					t.Errorf("%s with %s: transformation returned %s at %d", name, algorithm, result, attempt)
+				}
+			}
+		}
+	}
+}
+
+func FuzzTransformations(f *testing.F) {
+	f.Add(int64(0), 0.5, int64(0))
+	f.Add(int64(1), 0.0, int64(1))
+	f.Add(int64(-1), -1.5, int64(2))
+	f.Add(int64(math.MaxInt64), 2.0, int64(3))
+	f.Add(int64(math.MinInt64), math.Inf(1), int64(4))
+	f.Add(int64(time.Second), math.NaN(), int64(5))
+
+	f.Fuzz(func(t *testing.T, duration int64, factor float64, seed int64) {
+		generator := rand.New(rand.NewSource(seed))
+		tests := map[string]Transformation{
+			"full":                Full(generator),
+			"equal":               Equal(generator),
+			"deviation":           Deviation(generator, factor),
+			"normal distribution": NormalDistribution(generator, factor),
+		}
+		for name, transformation := range tests {
+			if result := transformation(time.Duration(duration)); result < 0 {
+				t.Errorf("%s: transformation returned %s for %s", name, result, time.Duration(duration))
+			}
+		}
+	})
+}
+
+// helpers
+
+func transformations(generator Generator) map[string]Transformation {
+	return map[string]Transformation{
+		"full":                Full(generator),
+		"equal":               Equal(generator),
+		"deviation":           Deviation(generator, 0.5),
+		"huge deviation":      Deviation(generator, 10),
+		"negative deviation":  Deviation(generator, -0.5),
+		"normal distribution": NormalDistribution(generator, float64(time.Second)),
+	}
+}
@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+// Local returns a value bound to the current retry process by the given key.
+// If there is no such value yet, it stores and returns the one created by
+// the given function. It allows stateful strategies to keep their state
+// per retry process, so concurrent processes don't share it.
+//
+//  type key struct{}
+//
+//  func(breaker strategy.Breaker, attempt uint, err error) bool {
+//  	counter, _ := retry.Local(breaker, key{}, func() *int { return new(int) })
+//  	*counter++
+//  	return *counter < 3
+//  }
+//
+// It returns false and the created value, if the breaker
+// was not passed by the retry process into a strategy or an action.
+func Local[T any](breaker Breaker, key interface{}, create func() T) (T, bool) {
+	ctx, is := breaker.(context.Context)
+	if !is {
+		return create(), false
+	}
+	current, is := ctx.Value(frameKey{}).(frame)
+	if !is || !current.active || current.scope == nil {
+		return create(), false
+	}
+	value, _ := current.scope.load(key, func() interface{} { return create() }).(T)
+	return value, true
+}
+
+type scope struct {
+	mu     sync.Mutex
+	values map[interface{}]interface{}
+}
+
+func (scope *scope) load(key interface{}, create func() interface{}) interface{} {
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	value, is := scope.values[key]
+	if !is {
+		if scope.values == nil {
+			scope.values = make(map[interface{}]interface{})
+		}
+		value = create()
+		scope.values[key] = value
+	}
+	return value
+}
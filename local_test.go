@@ -0,0 +1,69 @@
+package retry_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestLocal(t *testing.T) {
+	type key struct{}
+
+	counter := func(breaker Breaker) (int, bool) {
+		value, bound := Local(breaker, key{}, func() *int { return new(int) })
+		*value++
+		return *value, bound
+	}
+
+	t.Run("outside of the retry process", func(t *testing.T) {
+		if value, bound := counter(context.TODO()); bound || value != 1 {
+			t.Errorf("unexpected result: %d, %v", value, bound)
+		}
+		if value, bound := counter(make(signal)); bound || value != 1 {
+			t.Errorf("unexpected result: %d, %v", value, bound)
+		}
+	})
+
+	t.Run("bound to the retry process", func(t *testing.T) {
+		var values []int
+		observe := func(breaker strategy.Breaker, attempt uint, err error) bool {
+			value, bound := counter(breaker)
+			if !bound {
+				t.Error("value is not bound")
+			}
+			values = append(values, value)
+			return true
+		}
+		action := func(ctx context.Context) error {
+			value, _ := counter(ctx)
+			values = append(values, value)
+			return Error("failure")
+		}
+
+		_ = Do(breaker(), action, strategy.Limit(2), observe)
+		_ = Do(breaker(), action, strategy.Limit(1), observe)
+
+		expected := []int{1, 2, 3, 4, 1, 2}
+		if len(values) != len(expected) {
+			t.Fatalf("expected: %v, obtained: %v", expected, values)
+		}
+		for i := range expected {
+			if expected[i] != values[i] {
+				t.Fatalf("expected: %v, obtained: %v", expected, values)
+			}
+		}
+	})
+
+	t.Run("nil value", func(t *testing.T) {
+		action := func(ctx context.Context) error {
+			value, bound := Local(ctx, key{}, func() error { return nil })
+			if !bound || value != nil {
+				t.Errorf("unexpected result: %v, %v", value, bound)
+			}
+			return nil
+		}
+		_ = Do(breaker(), action)
+	})
+}
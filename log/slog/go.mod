@@ -0,0 +1,7 @@
+module github.com/kamilsk/retry/log/slog
+
+go 1.21
+
+require github.com/kamilsk/retry/v5 v5.0.0-rc8
+
+replace github.com/kamilsk/retry/v5 => ../../
@@ -0,0 +1,169 @@
+// Package slog provides a retry.Hook that logs the retry process
+// by the log/slog package.
+//
+//  hook := &slog.Hook{Operation: "fetch", Sample: slog.Every(10)}
+//  ctx := retry.With(ctx, retry.Observe(hook))
+//  err := retry.Do(ctx, action, how...)
+//
+package slog
+
+import (
+	"context"
+	stdslog "log/slog"
+	"sync/atomic"
+
+	"github.com/kamilsk/retry/v5"
+)
+
+// Hook logs failed attempts, delays before attempts, give-ups,
+// and breaker cancellations. It is safe for concurrent use.
+type Hook struct {
+	// Logger writes records. The default is the slog.Default.
+	Logger *stdslog.Logger
+	// Operation is added to records if it is not empty.
+	Operation string
+	// Levels of records.
+	Levels Levels
+	// Keys are names of record attributes.
+	Keys Keys
+	// Sample decides whether to log a failed attempt or a delay,
+	// everything is logged if it is nil. Give-ups and cancellations
+	// are not sampled.
+	Sample func(operation string, attempt retry.Attempt) bool
+}
+
+// Levels defines levels of records, nil values are replaced by defaults.
+type Levels struct {
+	// AttemptError is the level of failed attempts. The default is Warn.
+	AttemptError stdslog.Leveler
+	// Delay is the level of delays before attempts. The default is Debug.
+	Delay stdslog.Leveler
+	// GiveUp is the level of give-ups. The default is Error.
+	GiveUp stdslog.Leveler
+	// BreakerCancel is the level of breaker cancellations. The default is Info.
+	BreakerCancel stdslog.Leveler
+}
+
+// Keys defines names of record attributes, empty values are replaced by defaults.
+type Keys struct {
+	// Operation is "operation" by default.
+	Operation string
+	// Attempt is the zero-based number of an attempt, "attempt" by default.
+	Attempt string
+	// Delay is the time spent by strategies before an attempt, "delay" by default.
+	Delay string
+	// Duration is the time spent by an attempt, "duration" by default.
+	Duration string
+	// Elapsed is the time passed since the retry process has been started,
+	// "elapsed" by default.
+	Elapsed string
+	// Error is "error" by default.
+	Error string
+}
+
+// Every returns a sampler for the Hook.Sample that allows the first
+// and then every n-th record.
+func Every(n uint64) func(string, retry.Attempt) bool {
+	var counter uint64
+	return func(string, retry.Attempt) bool {
+		return n <= 1 || (atomic.AddUint64(&counter, 1)-1)%n == 0
+	}
+}
+
+// OnAttemptStart logs the delay before an attempt after the first.
+func (hook *Hook) OnAttemptStart(attempt retry.Attempt) {
+	if attempt.Number == 0 || !hook.sample(attempt) {
+		return
+	}
+	keys := hook.Keys.defaults()
+	hook.log(choose(hook.Levels.Delay, stdslog.LevelDebug), "retry: waited before attempt",
+		stdslog.Uint64(keys.Attempt, uint64(attempt.Number)),
+		stdslog.Duration(keys.Delay, attempt.Wait),
+		stdslog.Duration(keys.Elapsed, attempt.Elapsed()),
+	)
+}
+
+// OnAttemptError logs the failed attempt.
+func (hook *Hook) OnAttemptError(attempt retry.Attempt, err error) {
+	if !hook.sample(attempt) {
+		return
+	}
+	keys := hook.Keys.defaults()
+	hook.log(choose(hook.Levels.AttemptError, stdslog.LevelWarn), "retry: attempt failed",
+		stdslog.Uint64(keys.Attempt, uint64(attempt.Number)),
+		stdslog.Duration(keys.Duration, attempt.Duration),
+		stdslog.Duration(keys.Elapsed, attempt.Elapsed()),
+		stdslog.Any(keys.Error, err),
+	)
+}
+
+// OnSuccess does nothing.
+func (hook *Hook) OnSuccess(retry.Attempt) {}
+
+// OnGiveUp logs that strategies halted the retry process.
+func (hook *Hook) OnGiveUp(attempt retry.Attempt, err error) {
+	keys := hook.Keys.defaults()
+	hook.log(choose(hook.Levels.GiveUp, stdslog.LevelError), "retry: gave up",
+		stdslog.Uint64(keys.Attempt, uint64(attempt.Number)),
+		stdslog.Duration(keys.Elapsed, attempt.Elapsed()),
+		stdslog.Any(keys.Error, err),
+	)
+}
+
+// OnBreakerCancel logs that the breaker interrupted the retry process.
+func (hook *Hook) OnBreakerCancel(attempt retry.Attempt, err error) {
+	keys := hook.Keys.defaults()
+	hook.log(choose(hook.Levels.BreakerCancel, stdslog.LevelInfo), "retry: cancelled",
+		stdslog.Uint64(keys.Attempt, uint64(attempt.Number)),
+		stdslog.Duration(keys.Elapsed, attempt.Elapsed()),
+		stdslog.Any(keys.Error, err),
+	)
+}
+
+func (hook *Hook) log(level stdslog.Leveler, msg string, attrs ...stdslog.Attr) {
+	logger := hook.Logger
+	if logger == nil {
+		logger = stdslog.Default()
+	}
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level.Level()) {
+		return
+	}
+	if hook.Operation != "" {
+		attrs = append([]stdslog.Attr{stdslog.String(hook.Keys.defaults().Operation, hook.Operation)}, attrs...)
+	}
+	logger.LogAttrs(ctx, level.Level(), msg, attrs...)
+}
+
+func (hook *Hook) sample(attempt retry.Attempt) bool {
+	return hook.Sample == nil || hook.Sample(hook.Operation, attempt)
+}
+
+func (keys Keys) defaults() Keys {
+	if keys.Operation == "" {
+		keys.Operation = "operation"
+	}
+	if keys.Attempt == "" {
+		keys.Attempt = "attempt"
+	}
+	if keys.Delay == "" {
+		keys.Delay = "delay"
+	}
+	if keys.Duration == "" {
+		keys.Duration = "duration"
+	}
+	if keys.Elapsed == "" {
+		keys.Elapsed = "elapsed"
+	}
+	if keys.Error == "" {
+		keys.Error = "error"
+	}
+	return keys
+}
+
+func choose(level, fallback stdslog.Leveler) stdslog.Leveler {
+	if level == nil {
+		return fallback
+	}
+	return level
+}
@@ -0,0 +1,177 @@
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	stdslog "log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/log/slog"
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/backoff"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestHook(t *testing.T) {
+	failure := errors.New("failure")
+
+	t.Run("give up", func(t *testing.T) {
+		var buf bytes.Buffer
+		hook := &Hook{Logger: logger(&buf), Operation: "fetch"}
+
+		_ = retry.Do(retry.With(context.Background(), retry.Observe(hook)), func(context.Context) error {
+			return failure
+		}, strategy.Limit(2), strategy.Backoff(backoff.Constant(time.Millisecond)))
+
+		records := decode(t, &buf)
+		expected := []string{
+			"WARN retry: attempt failed",
+			"DEBUG retry: waited before attempt",
+			"WARN retry: attempt failed",
+			"ERROR retry: gave up",
+		}
+		if obtained := messages(records); !reflect.DeepEqual(obtained, expected) {
+			t.Fatalf("expected: %v, obtained: %v", expected, obtained)
+		}
+		for _, record := range records {
+			if record["operation"] != "fetch" {
+				t.Errorf("unexpected operation: %v", record["operation"])
+			}
+			if _, has := record["elapsed"]; !has {
+				t.Errorf("elapsed expected in %v", record)
+			}
+		}
+		if records[0]["error"] != "failure" || records[0]["attempt"] != 0.0 {
+			t.Errorf("unexpected attempt record: %v", records[0])
+		}
+		if delay := records[1]["delay"].(float64); delay < float64(time.Millisecond) || records[1]["attempt"] != 1.0 {
+			t.Errorf("unexpected delay record: %v", records[1])
+		}
+		if records[3]["error"] != "retry: gave up after 2 attempts: failure" {
+			t.Errorf("unexpected give up record: %v", records[3])
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		var buf bytes.Buffer
+		hook := &Hook{
+			Logger: logger(&buf),
+			Levels: Levels{AttemptError: stdslog.LevelDebug, BreakerCancel: stdslog.LevelWarn},
+			Keys:   Keys{Attempt: "try", Error: "err"},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		_ = retry.Do(retry.With(ctx, retry.Observe(hook)), func(context.Context) error {
+			cancel()
+			return failure
+		})
+
+		records := decode(t, &buf)
+		expected := []string{"DEBUG retry: attempt failed", "WARN retry: cancelled"}
+		if obtained := messages(records); !reflect.DeepEqual(obtained, expected) {
+			t.Fatalf("expected: %v, obtained: %v", expected, obtained)
+		}
+		if records[1]["err"] != "context canceled" || records[1]["try"] != 1.0 {
+			t.Errorf("unexpected cancel record: %v", records[1])
+		}
+		if _, has := records[1]["operation"]; has {
+			t.Errorf("operation is not expected in %v", records[1])
+		}
+	})
+
+	t.Run("disabled level", func(t *testing.T) {
+		var buf bytes.Buffer
+		hook := &Hook{
+			Logger: stdslog.New(stdslog.NewJSONHandler(&buf, &stdslog.HandlerOptions{Level: stdslog.LevelError})),
+		}
+
+		_ = retry.Do(retry.With(context.Background(), retry.Observe(hook)), func(context.Context) error {
+			return failure
+		}, strategy.Limit(3))
+
+		expected := []string{"ERROR retry: gave up"}
+		if obtained := messages(decode(t, &buf)); !reflect.DeepEqual(obtained, expected) {
+			t.Errorf("expected: %v, obtained: %v", expected, obtained)
+		}
+	})
+
+	t.Run("sample", func(t *testing.T) {
+		var buf bytes.Buffer
+		hook := &Hook{Logger: logger(&buf), Sample: Every(3)}
+
+		_ = retry.Do(retry.With(context.Background(), retry.Observe(hook)), func(context.Context) error {
+			return failure
+		}, strategy.Limit(4))
+
+		expected := []string{
+			"WARN retry: attempt failed",
+			"DEBUG retry: waited before attempt",
+			"WARN retry: attempt failed",
+			"ERROR retry: gave up",
+		}
+		records := decode(t, &buf)
+		if obtained := messages(records); !reflect.DeepEqual(obtained, expected) {
+			t.Fatalf("expected: %v, obtained: %v", expected, obtained)
+		}
+		if records[0]["attempt"] != 0.0 || records[1]["attempt"] != 2.0 || records[2]["attempt"] != 3.0 {
+			t.Errorf("unexpected sampled records: %v", records)
+		}
+	})
+}
+
+func TestEvery(t *testing.T) {
+	tests := map[string]struct {
+		n        uint64
+		expected []bool
+	}{
+		"zero":  {0, []bool{true, true, true}},
+		"one":   {1, []bool{true, true, true}},
+		"three": {3, []bool{true, false, false, true, false, false, true}},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			sample := Every(tc.n)
+			obtained := make([]bool, 0, len(tc.expected))
+			for range tc.expected {
+				obtained = append(obtained, sample("", retry.Attempt{}))
+			}
+			if !reflect.DeepEqual(obtained, tc.expected) {
+				t.Errorf("expected: %v, obtained: %v", tc.expected, obtained)
+			}
+		})
+	}
+}
+
+// helpers
+
+func logger(buf *bytes.Buffer) *stdslog.Logger {
+	return stdslog.New(stdslog.NewJSONHandler(buf, &stdslog.HandlerOptions{Level: stdslog.LevelDebug}))
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var records []map[string]interface{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var record map[string]interface{}
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func messages(records []map[string]interface{}) []string {
+	list := make([]string, 0, len(records))
+	for _, record := range records {
+		list = append(list, record["level"].(string)+" "+record["msg"].(string))
+	}
+	return list
+}
@@ -0,0 +1,75 @@
+// Package metrics provides a way to instrument the retry process
+// without depending on a specific metrics library.
+//
+//  ctx := retry.With(ctx, metrics.Observe("fetch", collector))
+//  err := retry.Do(ctx, action, how...)
+//
+// See the github.com/kamilsk/retry/metrics/prometheus module
+// for the Prometheus implementation of the Metrics.
+package metrics
+
+import (
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+)
+
+// Metrics collects measurements of retry processes
+// labeled by an operation name.
+type Metrics interface {
+	// IncAttempts is called before each attempt.
+	IncAttempts(operation string)
+	// IncRetries is called before each attempt after the first.
+	IncRetries(operation string)
+	// IncGiveUps is called when strategies halt the retry process.
+	IncGiveUps(operation string)
+	// IncCancellations is called when the breaker interrupts the retry process.
+	IncCancellations(operation string)
+	// ObserveDelay is called before each attempt after the first
+	// with the time spent by strategies.
+	ObserveDelay(operation string, delay time.Duration)
+	// ObserveAttempts is called when the retry process is finished
+	// with the number of attempts made.
+	ObserveAttempts(operation string, attempts uint)
+}
+
+// Observe creates an Option that reports the retry process
+// to the metrics under the operation name.
+func Observe(operation string, metrics Metrics) retry.Option {
+	return retry.Observe(Hook(operation, metrics))
+}
+
+// Hook returns a retry.Hook that reports the retry process
+// to the metrics under the operation name.
+func Hook(operation string, metrics Metrics) retry.Hook {
+	return hook{operation: operation, metrics: metrics}
+}
+
+type hook struct {
+	operation string
+	metrics   Metrics
+}
+
+func (hook hook) OnAttemptStart(attempt retry.Attempt) {
+	hook.metrics.IncAttempts(hook.operation)
+	if attempt.Number > 0 {
+		hook.metrics.IncRetries(hook.operation)
+		hook.metrics.ObserveDelay(hook.operation, attempt.Wait)
+	}
+}
+
+func (hook hook) OnAttemptError(retry.Attempt, error) {}
+
+func (hook hook) OnSuccess(attempt retry.Attempt) {
+	hook.metrics.ObserveAttempts(hook.operation, attempt.Number+1)
+}
+
+func (hook hook) OnGiveUp(attempt retry.Attempt, _ error) {
+	hook.metrics.IncGiveUps(hook.operation)
+	hook.metrics.ObserveAttempts(hook.operation, attempt.Number)
+}
+
+func (hook hook) OnBreakerCancel(attempt retry.Attempt, _ error) {
+	hook.metrics.IncCancellations(hook.operation)
+	hook.metrics.ObserveAttempts(hook.operation, attempt.Number)
+}
@@ -0,0 +1,124 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+	. "github.com/kamilsk/retry/v5/metrics"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestObserve(t *testing.T) {
+	failure := errors.New("failure")
+
+	tests := map[string]struct {
+		action   func(context.Context) error
+		how      retry.How
+		expected []string
+	}{
+		"success": {
+			action:   sequence(nil),
+			expected: []string{"fetch: attempt", "fetch: attempts 1"},
+		},
+		"eventual success": {
+			action: sequence(failure, failure, nil),
+			how:    retry.How{strategy.Limit(5)},
+			expected: []string{
+				"fetch: attempt",
+				"fetch: attempt", "fetch: retry", "fetch: delay",
+				"fetch: attempt", "fetch: retry", "fetch: delay",
+				"fetch: attempts 3",
+			},
+		},
+		"give up": {
+			action: sequence(failure, failure, failure),
+			how:    retry.How{strategy.Limit(2)},
+			expected: []string{
+				"fetch: attempt",
+				"fetch: attempt", "fetch: retry", "fetch: delay",
+				"fetch: give up", "fetch: attempts 2",
+			},
+		},
+		"cancel": {
+			action: sequence(failure, context.Canceled),
+			expected: []string{
+				"fetch: attempt",
+				"fetch: attempt", "fetch: retry", "fetch: delay",
+				"fetch: cancel", "fetch: attempts 2",
+			},
+		},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			action := func(ctx context.Context) error {
+				err := tc.action(ctx)
+				if errors.Is(err, context.Canceled) {
+					cancel()
+				}
+				return err
+			}
+
+			metrics := new(recorder)
+			_ = retry.Do(retry.With(ctx, Observe("fetch", metrics)), action, tc.how...)
+			if !reflect.DeepEqual(metrics.events, tc.expected) {
+				t.Errorf("expected: %v, obtained: %v", tc.expected, metrics.events)
+			}
+		})
+	}
+}
+
+func TestHook(t *testing.T) {
+	metrics := new(recorder)
+	hook := Hook("fetch", metrics)
+
+	hook.OnAttemptStart(retry.Attempt{Number: 1, Wait: time.Second})
+	if metrics.delay != time.Second {
+		t.Errorf("expected: %s, obtained: %s", time.Second, metrics.delay)
+	}
+}
+
+// helpers
+
+type recorder struct {
+	events []string
+	delay  time.Duration
+}
+
+func (r *recorder) IncAttempts(operation string) { r.record(operation, "attempt") }
+
+func (r *recorder) IncRetries(operation string) { r.record(operation, "retry") }
+
+func (r *recorder) IncGiveUps(operation string) { r.record(operation, "give up") }
+
+func (r *recorder) IncCancellations(operation string) { r.record(operation, "cancel") }
+
+func (r *recorder) ObserveDelay(operation string, delay time.Duration) {
+	r.record(operation, "delay")
+	r.delay += delay
+}
+
+func (r *recorder) ObserveAttempts(operation string, attempts uint) {
+	r.record(operation, fmt.Sprintf("attempts %d", attempts))
+}
+
+func (r *recorder) record(operation, event string) {
+	r.events = append(r.events, operation+": "+event)
+}
+
+func sequence(errs ...error) func(context.Context) error {
+	var i int
+	return func(context.Context) error {
+		err := errs[i]
+		i++
+		return err
+	}
+}
@@ -0,0 +1,139 @@
+// Package prometheus provides the Prometheus implementation
+// of the metrics.Metrics.
+//
+//  collector := prometheus.New(prometheus.Opts{Namespace: "app"})
+//  registry.MustRegister(collector)
+//
+//  ctx := retry.With(ctx, metrics.Observe("fetch", collector))
+//  err := retry.Do(ctx, action, how...)
+//
+package prometheus
+
+import (
+	"time"
+
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kamilsk/retry/v5/metrics"
+)
+
+// Label is the name of the label that holds an operation name.
+const Label = "operation"
+
+// Opts configures the names and buckets of the collected metrics.
+type Opts struct {
+	// Namespace and Subsystem are the prefixes of the metric names.
+	// The Subsystem is "retry" by default.
+	Namespace, Subsystem string
+	// ConstLabels are added to all metrics.
+	ConstLabels stdprometheus.Labels
+	// DelayBuckets are the buckets of the delay histogram in seconds.
+	// By default, they are exponential from 5ms to about 41s.
+	DelayBuckets []float64
+	// AttemptBuckets are the buckets of the attempts-per-call histogram.
+	// By default, they are 1, 2, 3, 5, 8, 13, and 21.
+	AttemptBuckets []float64
+}
+
+// Metrics collects measurements of retry processes.
+// It implements the metrics.Metrics and the prometheus.Collector.
+type Metrics struct {
+	attempts      *stdprometheus.CounterVec
+	retries       *stdprometheus.CounterVec
+	giveUps       *stdprometheus.CounterVec
+	cancellations *stdprometheus.CounterVec
+	delays        *stdprometheus.HistogramVec
+	calls         *stdprometheus.HistogramVec
+}
+
+var _ metrics.Metrics = (*Metrics)(nil)
+
+// New creates Metrics configured by the opts.
+// They must be registered to be exported.
+func New(opts Opts) *Metrics {
+	if opts.Subsystem == "" {
+		opts.Subsystem = "retry"
+	}
+	if opts.DelayBuckets == nil {
+		opts.DelayBuckets = stdprometheus.ExponentialBuckets(0.005, 2, 14)
+	}
+	if opts.AttemptBuckets == nil {
+		opts.AttemptBuckets = []float64{1, 2, 3, 5, 8, 13, 21}
+	}
+
+	counter := func(name, help string) *stdprometheus.CounterVec {
+		return stdprometheus.NewCounterVec(stdprometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        name,
+			Help:        help,
+			ConstLabels: opts.ConstLabels,
+		}, []string{Label})
+	}
+	histogram := func(name, help string, buckets []float64) *stdprometheus.HistogramVec {
+		return stdprometheus.NewHistogramVec(stdprometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        name,
+			Help:        help,
+			ConstLabels: opts.ConstLabels,
+			Buckets:     buckets,
+		}, []string{Label})
+	}
+	return &Metrics{
+		attempts:      counter("attempts_total", "Total number of attempts."),
+		retries:       counter("retries_total", "Total number of attempts after the first."),
+		giveUps:       counter("give_ups_total", "Total number of retry processes halted by strategies."),
+		cancellations: counter("cancellations_total", "Total number of retry processes interrupted by the breaker."),
+		delays:        histogram("delay_seconds", "Time spent by strategies before an attempt.", opts.DelayBuckets),
+		calls:         histogram("attempts_per_call", "Number of attempts made by a retry process.", opts.AttemptBuckets),
+	}
+}
+
+// IncAttempts increments the attempts counter.
+func (m *Metrics) IncAttempts(operation string) {
+	m.attempts.WithLabelValues(operation).Inc()
+}
+
+// IncRetries increments the retries counter.
+func (m *Metrics) IncRetries(operation string) {
+	m.retries.WithLabelValues(operation).Inc()
+}
+
+// IncGiveUps increments the give-ups counter.
+func (m *Metrics) IncGiveUps(operation string) {
+	m.giveUps.WithLabelValues(operation).Inc()
+}
+
+// IncCancellations increments the cancellations counter.
+func (m *Metrics) IncCancellations(operation string) {
+	m.cancellations.WithLabelValues(operation).Inc()
+}
+
+// ObserveDelay adds the delay to the delay histogram.
+func (m *Metrics) ObserveDelay(operation string, delay time.Duration) {
+	m.delays.WithLabelValues(operation).Observe(delay.Seconds())
+}
+
+// ObserveAttempts adds the number of attempts to the attempts-per-call histogram.
+func (m *Metrics) ObserveAttempts(operation string, attempts uint) {
+	m.calls.WithLabelValues(operation).Observe(float64(attempts))
+}
+
+// Describe implements the prometheus.Collector interface.
+func (m *Metrics) Describe(ch chan<- *stdprometheus.Desc) {
+	for _, collector := range m.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+// Collect implements the prometheus.Collector interface.
+func (m *Metrics) Collect(ch chan<- stdprometheus.Metric) {
+	for _, collector := range m.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+func (m *Metrics) collectors() []stdprometheus.Collector {
+	return []stdprometheus.Collector{m.attempts, m.retries, m.giveUps, m.cancellations, m.delays, m.calls}
+}
@@ -0,0 +1,123 @@
+package prometheus_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	. "github.com/kamilsk/retry/metrics/prometheus"
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/metrics"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestMetrics(t *testing.T) {
+	collector := New(Opts{Namespace: "app", AttemptBuckets: []float64{1, 2, 3}})
+	registry := stdprometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	failure := errors.New("failure")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fetch := retry.With(ctx, metrics.Observe("fetch", collector))
+	_ = retry.Do(fetch, sequence(failure, nil), strategy.Limit(3))
+	_ = retry.Do(fetch, sequence(failure, failure, failure), strategy.Limit(3))
+
+	store := retry.With(ctx, metrics.Observe("store", collector))
+	_ = retry.Do(store, func(context.Context) error {
+		cancel()
+		return failure
+	})
+
+	expected := `
+		# HELP app_retry_attempts_per_call Number of attempts made by a retry process.
+		# TYPE app_retry_attempts_per_call histogram
+		app_retry_attempts_per_call_bucket{operation="fetch",le="1"} 0
+		app_retry_attempts_per_call_bucket{operation="fetch",le="2"} 1
+		app_retry_attempts_per_call_bucket{operation="fetch",le="3"} 2
+		app_retry_attempts_per_call_bucket{operation="fetch",le="+Inf"} 2
+		app_retry_attempts_per_call_sum{operation="fetch"} 5
+		app_retry_attempts_per_call_count{operation="fetch"} 2
+		app_retry_attempts_per_call_bucket{operation="store",le="1"} 1
+		app_retry_attempts_per_call_bucket{operation="store",le="2"} 1
+		app_retry_attempts_per_call_bucket{operation="store",le="3"} 1
+		app_retry_attempts_per_call_bucket{operation="store",le="+Inf"} 1
+		app_retry_attempts_per_call_sum{operation="store"} 1
+		app_retry_attempts_per_call_count{operation="store"} 1
+		# HELP app_retry_attempts_total Total number of attempts.
+		# TYPE app_retry_attempts_total counter
+		app_retry_attempts_total{operation="fetch"} 5
+		app_retry_attempts_total{operation="store"} 1
+		# HELP app_retry_cancellations_total Total number of retry processes interrupted by the breaker.
+		# TYPE app_retry_cancellations_total counter
+		app_retry_cancellations_total{operation="store"} 1
+		# HELP app_retry_give_ups_total Total number of retry processes halted by strategies.
+		# TYPE app_retry_give_ups_total counter
+		app_retry_give_ups_total{operation="fetch"} 1
+		# HELP app_retry_retries_total Total number of attempts after the first.
+		# TYPE app_retry_retries_total counter
+		app_retry_retries_total{operation="fetch"} 3
+	`
+	names := []string{
+		"app_retry_attempts_per_call",
+		"app_retry_attempts_total",
+		"app_retry_cancellations_total",
+		"app_retry_give_ups_total",
+		"app_retry_retries_total",
+	}
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), names...); err != nil {
+		t.Error(err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var delays uint64
+	for _, family := range families {
+		if family.GetName() == "app_retry_delay_seconds" {
+			for _, metric := range family.GetMetric() {
+				delays += metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	if delays != 3 {
+		t.Errorf("expected: %d, obtained: %d", 3, delays)
+	}
+
+	if count := testutil.CollectAndCount(collector); count != 8 {
+		t.Errorf("expected: %d, obtained: %d", 8, count)
+	}
+}
+
+func TestNew(t *testing.T) {
+	collector := New(Opts{})
+	collector.IncAttempts("op")
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(`
+		# HELP retry_attempts_total Total number of attempts.
+		# TYPE retry_attempts_total counter
+		retry_attempts_total{operation="op"} 1
+	`), "retry_attempts_total"); err != nil {
+		t.Error(err)
+	}
+	if problems, err := testutil.CollectAndLint(collector); err != nil || len(problems) > 0 {
+		t.Errorf("unexpected lint result: %v, %v", problems, err)
+	}
+}
+
+// helpers
+
+func sequence(errs ...error) func(context.Context) error {
+	var i int
+	return func(context.Context) error {
+		err := errs[i]
+		i++
+		return err
+	}
+}
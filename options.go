@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// An Option configures the retry process started by Do or Go.
+type Option func(*options)
+
+// With returns a copy of the breaker as a context that configures
+// the retry process started by Do or Go with the given options.
+//
+//  ctx := retry.With(breaker, retry.Observe(hook))
+//  err := retry.Do(ctx, action, how...)
+//
+// The options are not inherited by the retry processes started
+// inside the action.
+func With(breaker Breaker, options ...Option) context.Context {
+	ctx := convert(breaker)
+	cfg := configure(ctx).clone()
+	for _, option := range options {
+		option(&cfg)
+	}
+	return context.WithValue(ctx, frameKey{}, frame{options: &cfg})
+}
+
+// Observe creates an Option that notifies the hooks about
+// the retry process lifecycle events.
+func Observe(hooks ...Hook) Option {
+	return func(cfg *options) {
+		cfg.hooks = append(cfg.hooks, hooks...)
+	}
+}
+
+type options struct {
+	aggregate bool
+	hooks     hooks
+	timeout   func(context.Context) time.Duration
+}
+
+func (cfg options) clone() options {
+	cfg.hooks = append(hooks(nil), cfg.hooks...)
+	return cfg
+}
+
+func (cfg options) attemptTimeout(ctx context.Context) time.Duration {
+	if cfg.timeout == nil {
+		return 0
+	}
+	return cfg.timeout(ctx)
+}
+
+// frame is stored in a context passed through Do or Go.
+// It holds options for the next retry process or, being active,
+// metadata about the current attempt of the running one.
+type frame struct {
+	active  bool
+	attempt Attempt
+	options *options
+	scope   *scope
+}
+
+type frameKey struct{}
+
+func attach(ctx context.Context, attempt Attempt, scope *scope) context.Context {
+	return context.WithValue(ctx, frameKey{}, frame{active: true, attempt: attempt, scope: scope})
+}
+
+func configure(ctx context.Context) options {
+	current, is := ctx.Value(frameKey{}).(frame)
+	if !is || current.active || current.options == nil {
+		return options{}
+	}
+	return *current.options
+}
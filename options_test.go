@@ -0,0 +1,58 @@
+package retry_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestWith(t *testing.T) {
+	t.Run("breaker", func(t *testing.T) {
+		sig := make(signal)
+		ctx := With(sig)
+		if ctx.Err() != nil {
+			t.Error("invalid state")
+		}
+
+		close(sig)
+		if ctx.Err() == nil {
+			t.Error("invalid state")
+		}
+	})
+
+	t.Run("preserve context values", func(t *testing.T) {
+		val := "value"
+		ctx := With(context.WithValue(context.TODO(), key{}, val))
+		if ctx.Value(key{}) != val {
+			t.Error("value is not preserved")
+		}
+		if _, is := Info(ctx); is {
+			t.Error("unexpected metadata")
+		}
+	})
+
+	t.Run("accumulate options", func(t *testing.T) {
+		var first, second int
+		ctx := With(breaker(), Observe(Hooks{AttemptStart: func(Attempt) { first++ }}))
+		ctx = With(ctx, Observe(Hooks{AttemptStart: func(Attempt) { second++ }}))
+
+		_ = Do(ctx, func(context.Context) error { return nil })
+		if first != 1 || second != 1 {
+			t.Errorf("unexpected number of calls: %d, %d", first, second)
+		}
+	})
+
+	t.Run("do not inherit options", func(t *testing.T) {
+		var calls int
+		ctx := With(breaker(), Observe(Hooks{AttemptStart: func(Attempt) { calls++ }}))
+
+		_ = Do(ctx, func(ctx context.Context) error {
+			return Do(ctx, func(context.Context) error { return nil }, strategy.Limit(3))
+		})
+		if calls != 1 {
+			t.Errorf("expected: %d, obtained: %d", 1, calls)
+		}
+	})
+}
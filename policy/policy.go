@@ -0,0 +1,354 @@
+// Package policy provides a declarative description of the retry process
+// that can be loaded from configuration files and compiled into strategies.
+//
+//  {
+//  	"attempts": 5,
+//  	"backoff": {"kind": "exponential", "duration": "100ms", "base": 2, "max": "10s"},
+//  	"jitter": {"kind": "full"},
+//  	"timeout": "2s",
+//  	"retriable": ["timeout", "temporary"]
+//  }
+//
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/backoff"
+	"github.com/kamilsk/retry/v5/jitter"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+// ErrInvalid is a sentinel to check that a policy is not valid,
+// see ValidationError.
+const ErrInvalid retry.Error = "policy: invalid configuration"
+
+// ValidationError is returned when a policy is not valid.
+type ValidationError struct {
+	// Field is a path to the invalid field, e.g. "backoff.kind".
+	Field string
+	// Reason describes what is wrong with the field.
+	Reason string
+}
+
+// Error returns a string representation of an error.
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("policy: %s: %s", err.Field, err.Reason)
+}
+
+// Is reports whether the target is the ErrInvalid.
+func (err *ValidationError) Is(target error) bool { return target == ErrInvalid }
+
+// Policy describes the retry process.
+type Policy struct {
+	// Attempts is the maximum number of attempts, it must be positive.
+	Attempts uint `json:"attempts" yaml:"attempts" toml:"attempts"`
+	// Backoff defines delays between attempts, no delays if omitted.
+	Backoff *Backoff `json:"backoff,omitempty" yaml:"backoff,omitempty" toml:"backoff,omitempty"`
+	// Jitter randomizes delays defined by the Backoff.
+	Jitter *Jitter `json:"jitter,omitempty" yaml:"jitter,omitempty" toml:"jitter,omitempty"`
+	// Timeout limits each attempt, see retry.AttemptTimeout.
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty" toml:"timeout,omitempty"`
+	// Retriable lists the Classes of errors that are retried,
+	// any error is retried if omitted.
+	Retriable []string `json:"retriable,omitempty" yaml:"retriable,omitempty" toml:"retriable,omitempty"`
+}
+
+// Backoff describes a backoff.Algorithm.
+type Backoff struct {
+	// Kind is one of "constant", "incremental", "linear",
+	// "exponential", and "fibonacci".
+	Kind string `json:"kind" yaml:"kind" toml:"kind"`
+	// Duration is the initial duration or the factor of the algorithm.
+	Duration Duration `json:"duration" yaml:"duration" toml:"duration"`
+	// Increment is used by the "incremental" kind.
+	Increment Duration `json:"increment,omitempty" yaml:"increment,omitempty" toml:"increment,omitempty"`
+	// Base is used by the "exponential" kind, it is 2 if omitted.
+	Base float64 `json:"base,omitempty" yaml:"base,omitempty" toml:"base,omitempty"`
+	// Max caps the calculated durations, see backoff.Cap.
+	Max Duration `json:"max,omitempty" yaml:"max,omitempty" toml:"max,omitempty"`
+}
+
+// Jitter describes a jitter.Transformation.
+type Jitter struct {
+	// Kind is one of "full", "equal", "deviation", and "normal".
+	Kind string `json:"kind" yaml:"kind" toml:"kind"`
+	// Factor is used by the "deviation" kind, it must be in (0, 1].
+	Factor float64 `json:"factor,omitempty" yaml:"factor,omitempty" toml:"factor,omitempty"`
+	// Deviation is the standard deviation used by the "normal" kind.
+	Deviation Duration `json:"deviation,omitempty" yaml:"deviation,omitempty" toml:"deviation,omitempty"`
+}
+
+// Classes contains the error classes that can be listed
+// in the Policy.Retriable. It can be extended on initialization
+// and must not be modified concurrently with compiling policies.
+var Classes = map[string]func(error) bool{
+	"eof": func(err error) bool {
+		return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	},
+	"network": func(err error) bool {
+		var target net.Error
+		return errors.As(err, &target)
+	},
+	"retry-after": func(err error) bool {
+		var target interface{ RetryAfter() time.Duration }
+		return errors.As(err, &target)
+	},
+	"temporary": func(err error) bool {
+		var target interface{ Temporary() bool }
+		return errors.As(err, &target) && target.Temporary()
+	},
+	"timeout": func(err error) bool {
+		var target interface{ Timeout() bool }
+		return errors.Is(err, context.DeadlineExceeded) ||
+			errors.As(err, &target) && target.Timeout()
+	},
+}
+
+// Load decodes a JSON policy from the reader and validates it.
+// Unknown fields are reported as errors.
+func Load(r io.Reader) (Policy, error) {
+	var policy Policy
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return policy, fmt.Errorf("policy: decode: %w", err)
+	}
+	return policy, policy.Validate()
+}
+
+// Validate checks the policy and returns a ValidationError
+// describing the first problem found.
+func (policy Policy) Validate() error {
+	if policy.Attempts == 0 {
+		return invalid("attempts", "must be positive")
+	}
+	if policy.Timeout < 0 {
+		return invalid("timeout", "must not be negative, obtained %s", policy.Timeout)
+	}
+	if policy.Backoff != nil {
+		if err := policy.Backoff.validate(); err != nil {
+			return err
+		}
+	}
+	if policy.Jitter != nil {
+		if policy.Backoff == nil {
+			return invalid("jitter", "requires backoff")
+		}
+		if err := policy.Jitter.validate(); err != nil {
+			return err
+		}
+	}
+	for i, class := range policy.Retriable {
+		if _, is := Classes[class]; !is {
+			return invalid(fmt.Sprintf("retriable[%d]", i),
+				"unknown class %q, expected one of %s", class, names(Classes))
+		}
+	}
+	return nil
+}
+
+// How validates the policy and compiles it into strategies.
+// Use Options to apply the per-attempt timeout.
+//
+//  how, err := policy.How()
+//  if err != nil {
+//  	return err
+//  }
+//  return retry.Do(retry.With(ctx, policy.Options()...), action, how...)
+//
+func (policy Policy) How() (retry.How, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	how := retry.How{strategy.Limit(policy.Attempts)}
+	if len(policy.Retriable) > 0 {
+		classes := make([]func(error) bool, 0, len(policy.Retriable))
+		for _, class := range policy.Retriable {
+			classes = append(classes, Classes[class])
+		}
+		how = append(how, retriable(classes))
+	}
+	if policy.Backoff != nil {
+		algorithm := policy.Backoff.algorithm()
+		if policy.Jitter == nil {
+			how = append(how, strategy.Backoff(algorithm))
+		} else {
+			generator := jitter.Locked(rand.New(rand.NewSource(time.Now().UnixNano())))
+			how = append(how, strategy.BackoffWithJitter(algorithm, policy.Jitter.transformation(generator)))
+		}
+	}
+	return how, nil
+}
+
+// Options returns options that configure the retry process
+// by the policy, see retry.With.
+func (policy Policy) Options() []retry.Option {
+	var options []retry.Option
+	if policy.Timeout > 0 {
+		options = append(options, retry.AttemptTimeout(time.Duration(policy.Timeout)))
+	}
+	return options
+}
+
+var algorithms = map[string]func(Backoff) backoff.Algorithm{
+	"constant": func(b Backoff) backoff.Algorithm {
+		return backoff.Constant(time.Duration(b.Duration))
+	},
+	"incremental": func(b Backoff) backoff.Algorithm {
+		return backoff.Incremental(time.Duration(b.Duration), time.Duration(b.Increment))
+	},
+	"linear": func(b Backoff) backoff.Algorithm {
+		return backoff.Linear(time.Duration(b.Duration))
+	},
+	"exponential": func(b Backoff) backoff.Algorithm {
+		base := b.Base
+		if base == 0 {
+			base = 2
+		}
+		return backoff.Exponential(time.Duration(b.Duration), base)
+	},
+	"fibonacci": func(b Backoff) backoff.Algorithm {
+		return backoff.Fibonacci(time.Duration(b.Duration))
+	},
+}
+
+func (b Backoff) validate() error {
+	if _, is := algorithms[b.Kind]; !is {
+		return invalid("backoff.kind", "unknown kind %q, expected one of %s", b.Kind, names(algorithms))
+	}
+	if b.Duration <= 0 {
+		return invalid("backoff.duration", "must be positive, obtained %s", b.Duration)
+	}
+	if b.Increment < 0 {
+		return invalid("backoff.increment", "must not be negative, obtained %s", b.Increment)
+	}
+	if b.Base < 0 || b.Base > 0 && b.Base < 1 {
+		return invalid("backoff.base", "must not be less than 1, obtained %v", b.Base)
+	}
+	if b.Max < 0 {
+		return invalid("backoff.max", "must not be negative, obtained %s", b.Max)
+	}
+	return nil
+}
+
+func (b Backoff) algorithm() backoff.Algorithm {
+	algorithm := algorithms[b.Kind](b)
+	if b.Max > 0 {
+		algorithm = backoff.Cap(algorithm, time.Duration(b.Max))
+	}
+	return algorithm
+}
+
+var transformations = map[string]func(Jitter, jitter.Generator) jitter.Transformation{
+	"full": func(_ Jitter, generator jitter.Generator) jitter.Transformation {
+		return jitter.Full(generator)
+	},
+	"equal": func(_ Jitter, generator jitter.Generator) jitter.Transformation {
+		return jitter.Equal(generator)
+	},
+	"deviation": func(j Jitter, generator jitter.Generator) jitter.Transformation {
+		return jitter.Deviation(generator, j.Factor)
+	},
+	"normal": func(j Jitter, generator jitter.Generator) jitter.Transformation {
+		return jitter.NormalDistribution(generator, float64(j.Deviation))
+	},
+}
+
+func (j Jitter) validate() error {
+	if _, is := transformations[j.Kind]; !is {
+		return invalid("jitter.kind", "unknown kind %q, expected one of %s", j.Kind, names(transformations))
+	}
+	if j.Kind == "deviation" && (j.Factor <= 0 || j.Factor > 1) {
+		return invalid("jitter.factor", "must be in (0, 1], obtained %v", j.Factor)
+	}
+	if j.Kind == "normal" && j.Deviation <= 0 {
+		return invalid("jitter.deviation", "must be positive, obtained %s", j.Deviation)
+	}
+	return nil
+}
+
+func (j Jitter) transformation(generator jitter.Generator) jitter.Transformation {
+	return transformations[j.Kind](j, generator)
+}
+
+// Duration is a time.Duration that is encoded as a string like "1m30s".
+// It also accepts a JSON number of nanoseconds.
+type Duration time.Duration
+
+// String returns a string representation of the duration.
+func (d Duration) String() string { return time.Duration(d).String() }
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		text, err := strconv.Unquote(string(data))
+		if err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(text))
+	}
+	nanoseconds, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	*d = Duration(nanoseconds)
+	return nil
+}
+
+func invalid(field, reason string, args ...interface{}) error {
+	return &ValidationError{Field: field, Reason: fmt.Sprintf(reason, args...)}
+}
+
+func names[T any](registry map[string]T) string {
+	list := make([]string, 0, len(registry))
+	for name := range registry {
+		list = append(list, strconv.Quote(name))
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
+
+func retriable(classes []func(error) bool) strategy.Strategy {
+	return func(_ strategy.Breaker, attempt uint, err error) bool {
+		if attempt == 0 || err == nil {
+			return true
+		}
+		for _, class := range classes {
+			if class(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,233 @@
+package policy_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+	. "github.com/kamilsk/retry/v5/policy"
+)
+
+func TestLoad(t *testing.T) {
+	policy, err := Load(strings.NewReader(`{
+		"attempts": 5,
+		"backoff": {"kind": "exponential", "duration": "100ms", "max": "10s"},
+		"jitter": {"kind": "deviation", "factor": 0.25},
+		"timeout": 2000000000,
+		"retriable": ["timeout", "eof"]
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Policy{
+		Attempts:  5,
+		Backoff:   &Backoff{Kind: "exponential", Duration: Duration(100 * time.Millisecond), Max: Duration(10 * time.Second)},
+		Jitter:    &Jitter{Kind: "deviation", Factor: 0.25},
+		Timeout:   Duration(2 * time.Second),
+		Retriable: []string{"timeout", "eof"},
+	}
+	obtained, _ := json.Marshal(policy)
+	if raw, _ := json.Marshal(expected); string(obtained) != string(raw) {
+		t.Errorf("expected: %s, obtained: %s", raw, obtained)
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		if _, err := Load(strings.NewReader(`{"attempts": 1, "delay": "1s"}`)); err == nil {
+			t.Error("error expected")
+		}
+	})
+	t.Run("invalid duration", func(t *testing.T) {
+		if _, err := Load(strings.NewReader(`{"attempts": 1, "timeout": "soon"}`)); err == nil {
+			t.Error("error expected")
+		}
+	})
+	t.Run("invalid policy", func(t *testing.T) {
+		if _, err := Load(strings.NewReader(`{"attempts": 0}`)); !errors.Is(err, ErrInvalid) {
+			t.Errorf("expected: %v, obtained: %v", ErrInvalid, err)
+		}
+	})
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	backoff := &Backoff{Kind: "constant", Duration: Duration(time.Millisecond)}
+
+	tests := map[string]struct {
+		policy Policy
+		field  string
+	}{
+		"valid": {
+			policy: Policy{Attempts: 1, Backoff: backoff, Jitter: &Jitter{Kind: "full"}},
+		},
+		"no attempts": {
+			policy: Policy{},
+			field:  "attempts",
+		},
+		"negative timeout": {
+			policy: Policy{Attempts: 1, Timeout: -1},
+			field:  "timeout",
+		},
+		"unknown backoff": {
+			policy: Policy{Attempts: 1, Backoff: &Backoff{Kind: "random", Duration: 1}},
+			field:  "backoff.kind",
+		},
+		"no backoff duration": {
+			policy: Policy{Attempts: 1, Backoff: &Backoff{Kind: "linear"}},
+			field:  "backoff.duration",
+		},
+		"negative increment": {
+			policy: Policy{Attempts: 1, Backoff: &Backoff{Kind: "incremental", Duration: 1, Increment: -1}},
+			field:  "backoff.increment",
+		},
+		"fractional base": {
+			policy: Policy{Attempts: 1, Backoff: &Backoff{Kind: "exponential", Duration: 1, Base: 0.5}},
+			field:  "backoff.base",
+		},
+		"negative max": {
+			policy: Policy{Attempts: 1, Backoff: &Backoff{Kind: "fibonacci", Duration: 1, Max: -1}},
+			field:  "backoff.max",
+		},
+		"jitter without backoff": {
+			policy: Policy{Attempts: 1, Jitter: &Jitter{Kind: "full"}},
+			field:  "jitter",
+		},
+		"unknown jitter": {
+			policy: Policy{Attempts: 1, Backoff: backoff, Jitter: &Jitter{Kind: "chaos"}},
+			field:  "jitter.kind",
+		},
+		"invalid factor": {
+			policy: Policy{Attempts: 1, Backoff: backoff, Jitter: &Jitter{Kind: "deviation", Factor: 2}},
+			field:  "jitter.factor",
+		},
+		"no deviation": {
+			policy: Policy{Attempts: 1, Backoff: backoff, Jitter: &Jitter{Kind: "normal"}},
+			field:  "jitter.deviation",
+		},
+		"unknown class": {
+			policy: Policy{Attempts: 1, Retriable: []string{"timeout", "cosmic rays"}},
+			field:  "retriable[1]",
+		},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.field == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expected validation error, obtained: %v", err)
+			}
+			if invalid.Field != tc.field {
+				t.Errorf("expected: %s, obtained: %s", tc.field, invalid.Field)
+			}
+			if !errors.Is(err, ErrInvalid) || !strings.HasPrefix(err.Error(), "policy: "+tc.field+": ") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPolicy_How(t *testing.T) {
+	t.Run("attempts", func(t *testing.T) {
+		policy := Policy{
+			Attempts: 3,
+			Backoff:  &Backoff{Kind: "linear", Duration: Duration(time.Millisecond)},
+			Jitter:   &Jitter{Kind: "equal"},
+		}
+		how, err := policy.How()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var made int
+		_ = retry.Do(context.Background(), func(context.Context) error {
+			made++
+			return io.EOF
+		}, how...)
+		if made != 3 {
+			t.Errorf("expected: %d, obtained: %d", 3, made)
+		}
+	})
+	t.Run("retriable", func(t *testing.T) {
+		policy := Policy{Attempts: 5, Retriable: []string{"eof"}}
+		how, err := policy.How()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var made int
+		errs := []error{io.ErrUnexpectedEOF, io.EOF, io.ErrClosedPipe, io.EOF}
+		err = retry.Do(context.Background(), func(context.Context) error {
+			made++
+			return errs[made-1]
+		}, how...)
+		if made != 3 || !errors.Is(err, io.ErrClosedPipe) {
+			t.Errorf("unexpected result: %d attempts, %v", made, err)
+		}
+	})
+	t.Run("timeout", func(t *testing.T) {
+		policy := Policy{Attempts: 2, Timeout: Duration(time.Millisecond), Retriable: []string{"timeout"}}
+		how, err := policy.How()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var made int
+		err = retry.Do(retry.With(context.Background(), policy.Options()...), func(ctx context.Context) error {
+			made++
+			<-ctx.Done()
+			return ctx.Err()
+		}, how...)
+		if made != 2 || !errors.Is(err, retry.ErrAttemptTimeout) {
+			t.Errorf("unexpected result: %d attempts, %v", made, err)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := (Policy{}).How(); !errors.Is(err, ErrInvalid) {
+			t.Errorf("expected: %v, obtained: %v", ErrInvalid, err)
+		}
+	})
+}
+
+func TestDuration(t *testing.T) {
+	tests := map[string]struct {
+		raw      string
+		expected Duration
+		invalid  bool
+	}{
+		"string":  {raw: `"1m30s"`, expected: Duration(90 * time.Second)},
+		"number":  {raw: `1500`, expected: Duration(1500)},
+		"null":    {raw: `null`},
+		"invalid": {raw: `"1 minute"`, invalid: true},
+		"float":   {raw: `1.5`, invalid: true},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			var obtained Duration
+			err := json.Unmarshal([]byte(tc.raw), &obtained)
+			if tc.invalid != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if obtained != tc.expected {
+				t.Errorf("expected: %s, obtained: %s", tc.expected, obtained)
+			}
+		})
+	}
+
+	raw, err := json.Marshal(Duration(time.Second))
+	if err != nil || string(raw) != `"1s"` {
+		t.Errorf("unexpected encoding: %s, %v", raw, err)
+	}
+}
@@ -3,6 +3,8 @@ package retry
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 )
 
 // Action defines a callable function that package retry can handle.
@@ -31,37 +33,68 @@ type How = []func(Breaker, uint, error) bool
 // Do takes the action and performs it, repetitively, until successful.
 //
 // Optionally, strategies may be passed that assess whether or not an attempt
-// should be made.
+// should be made. Strategies and the action receive a Breaker that carries
+// metadata about the current attempt, see Info. The process can be
+// configured by options passed through the breaker, see With.
+//
+// If strategies halt the process, Do returns the ExhaustedError.
+// If the breaker interrupts the process, Do returns the CancelledError.
 func Do(
 	breaker Breaker,
 	action func(context.Context) error,
 	strategies ...func(Breaker, uint, error) bool,
+) error {
+	return do(breaker, action, track(breaker), strategies)
+}
+
+func do(
+	breaker Breaker,
+	action func(context.Context) error,
+	state *progress,
+	strategies []func(Breaker, uint, error) bool,
 ) error {
 	var (
 		ctx        = convert(breaker)
+		info       = state.current
+		vars       = new(scope)
 		err  error = internal
 		core error
 	)
 
 	for attempt, should := uint(0), true; should; attempt++ {
 		core = unwrap(err)
+		info.Number, info.Wait = attempt, 0
+		if attempt > 0 {
+			info.Err, info.Cause = err, core
+		}
+
+		current, begin := attach(ctx, info, vars), time.Now()
 		for i, repeat := 0, len(strategies); should && i < repeat; i++ {
-			should = should && strategies[i](breaker, attempt, core)
+			should = should && strategies[i](current, attempt, core)
 		}
+		info.Wait = time.Since(begin)
+		info.Delay += info.Wait
 
 		select {
 		case <-breaker.Done():
-			return breaker.Err()
+			return state.cancelled(info, breaker.Err())
 		default:
 			if should {
-				err = action(ctx)
+				state.start(info)
+				begin = time.Now()
+				err = perform(attach(ctx, info, vars), action, state.cfg.attemptTimeout(ctx))
+				info.Previous, info.Duration = begin, time.Since(begin)
+				state.finish(info, err)
 			}
 		}
 
 		should = should && err != nil
 	}
 
-	return err
+	if err != nil {
+		return state.exhausted(info, err)
+	}
+	return nil
 }
 
 // Go takes the action and performs it, repetitively, until successful.
@@ -74,26 +107,108 @@ func Go(
 	action func(context.Context) error,
 	strategies ...func(Breaker, uint, error) bool,
 ) error {
-	done := make(chan error, 1)
-
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				err, ok := r.(error)
-				if !ok {
-					err = fmt.Errorf("retry: unexpected panic: %#v", r)
-				}
-				done <- err
-			}
-			close(done)
-		}()
-		done <- Do(breaker, action, strategies...)
-	}()
-
-	select {
-	case <-breaker.Done():
-		return breaker.Err()
-	case err := <-done:
-		return err
+	_, err := GoValue(breaker, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, action(ctx)
+	}, strategies...)
+	return err
+}
+
+// progress is the state of the retry process shared with GoValue,
+// which reports it if the breaker fires while an attempt is running.
+// It notifies the hooks until the process is over, so an attempt
+// orphaned by GoValue doesn't report its events.
+type progress struct {
+	cfg options
+
+	mu      sync.Mutex
+	over    bool
+	current Attempt
+	made    uint
+	last    error
+	errs    collector
+}
+
+func track(breaker Breaker) *progress {
+	cfg := configure(convert(breaker))
+	return &progress{
+		cfg:     cfg,
+		current: Attempt{Start: time.Now()},
+		errs:    collector{enabled: cfg.aggregate},
 	}
 }
+
+func (state *progress) start(info Attempt) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.over {
+		return
+	}
+	state.current = info
+	state.made++
+	state.cfg.hooks.OnAttemptStart(info)
+}
+
+func (state *progress) finish(info Attempt, err error) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.over {
+		return
+	}
+	state.last = err
+	state.errs.collect(info.Number, err)
+	if info.Err, info.Cause = err, unwrap(err); err == nil {
+		state.cfg.hooks.OnSuccess(info)
+	} else {
+		state.cfg.hooks.OnAttemptError(info, err)
+	}
+}
+
+func (state *progress) cancelled(info Attempt, cause error) error {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.over {
+		state.over = true
+		state.cfg.hooks.OnBreakerCancel(info, cause)
+	}
+	if state.made == 0 {
+		return &CancelledError{Cause: cause}
+	}
+	return &CancelledError{Attempts: state.made, Cause: cause, Err: state.errs.result(state.last)}
+}
+
+// interrupt finishes the process when the breaker fires
+// while an attempt may still be running.
+func (state *progress) interrupt(cause error) error {
+	state.mu.Lock()
+	info := state.current
+	if state.made > 0 {
+		info.Number = state.made
+	}
+	state.mu.Unlock()
+
+	return state.cancelled(info, cause)
+}
+
+func (state *progress) exhausted(info Attempt, err error) error {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	err = &ExhaustedError{Attempts: state.made, Err: state.errs.result(err)}
+	if !state.over {
+		state.over = true
+		info.Err, info.Cause = err, unwrap(err)
+		state.cfg.hooks.OnGiveUp(info, err)
+	}
+	return err
+}
+
+func recovered(r interface{}) error {
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("retry: unexpected panic: %#v", r)
+	}
+	return err
+}
@@ -124,6 +124,18 @@ type layer struct{ error }
 
 func (layer layer) Unwrap() error { return layer.error }
 
+type signal chan struct{}
+
+func (sig signal) Done() <-chan struct{} { return sig }
+func (sig signal) Err() error {
+	select {
+	case <-sig:
+		return context.Canceled
+	default:
+		return nil
+	}
+}
+
 type testCase struct {
 	name       string
 	breaker    Breaker
@@ -145,20 +157,20 @@ var testCases = []testCase{
 		breaker(),
 		How{strategy.Limit(10)},
 		func(context.Context) error { return layer{causer{Error("failure")}} },
-		expected{10, layer{causer{Error("failure")}}},
+		expected{10, &ExhaustedError{10, layer{causer{Error("failure")}}}},
 	},
 	{
 		"action call with interrupted breaker",
 		interrupted(),
 		How{strategy.Delay(time.Hour)},
 		func(context.Context) error { return Error("zero iterations") },
-		expected{0, context.Canceled},
+		expected{0, &CancelledError{Cause: context.Canceled}},
 	},
 	{
 		"have no action call",
 		breaker(),
 		How{strategy.Limit(0)},
 		func(context.Context) error { return layer{causer{Error("failure")}} },
-		expected{0, Error("have no any try")},
+		expected{0, &ExhaustedError{0, Error("have no any try")}},
 	},
 }
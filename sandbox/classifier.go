@@ -0,0 +1,215 @@
+package sandbox
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kamilsk/retry/v5/clock"
+)
+
+// Outcome is a result of an error classification.
+type Outcome struct {
+	retry bool
+	delay time.Duration
+}
+
+var (
+	// Retry is an Outcome that allows the next attempt.
+	Retry = Outcome{retry: true}
+	// Halt is an Outcome that halts the retrying process.
+	Halt = Outcome{}
+)
+
+// RetryAfter returns an Outcome that allows the next attempt
+// after the given delay.
+func RetryAfter(delay time.Duration) Outcome {
+	return Outcome{retry: true, delay: delay}
+}
+
+// Retriable reports whether the next attempt is allowed.
+func (outcome Outcome) Retriable() bool { return outcome.retry }
+
+// Delay returns the time to wait before the next attempt.
+func (outcome Outcome) Delay() time.Duration { return outcome.delay }
+
+// Rule defines a function that classifies an error.
+// It returns false if the error is not matched by the rule.
+type Rule = func(error) (Outcome, bool)
+
+// Is creates a Rule that matches errors by errors.Is with the target.
+func Is(target error, outcome Outcome) Rule {
+	return func(err error) (Outcome, bool) {
+		return outcome, errors.Is(err, target)
+	}
+}
+
+// As creates a Rule that matches errors by errors.As with the type T.
+//
+//  rule := sandbox.As[*net.DNSError](sandbox.Retry)
+//
+func As[T error](outcome Outcome) Rule {
+	return AsFunc(func(T) Outcome { return outcome })
+}
+
+// AsFunc creates a Rule that matches errors by errors.As with the type T
+// and classifies them by the given function.
+//
+//  rule := sandbox.AsFunc(func(err *HTTPError) sandbox.Outcome {
+//  	return sandbox.RetryAfter(err.RetryAfter())
+//  })
+//
+func AsFunc[T error](classify func(T) Outcome) Rule {
+	return func(err error) (Outcome, bool) {
+		var target T
+		if errors.As(err, &target) {
+			return classify(target), true
+		}
+		return Halt, false
+	}
+}
+
+// When creates a Rule that matches errors by the predicate.
+func When(predicate func(error) bool, outcome Outcome) Rule {
+	return func(err error) (Outcome, bool) {
+		return outcome, predicate(err)
+	}
+}
+
+// Pattern creates a Rule that matches errors by their messages.
+func Pattern(pattern *regexp.Regexp, outcome Outcome) Rule {
+	return func(err error) (Outcome, bool) {
+		return outcome, pattern.MatchString(err.Error())
+	}
+}
+
+// Declared creates a Rule that matches errors declaring themselves
+// as retriable or not by the Retriable method, see CheckError.
+func Declared() Rule {
+	return func(err error) (Outcome, bool) {
+		var target interface{ Retriable() bool }
+		if !errors.As(err, &target) {
+			return Halt, false
+		}
+		if target.Retriable() {
+			return Retry, true
+		}
+		return Halt, true
+	}
+}
+
+// Classifier is a registry of Rules. The rules with higher priority
+// are applied first, the ones with equal priority are applied
+// in the order of registration. It is safe for concurrent use.
+//
+//  var payments sandbox.Classifier
+//  payments.Register(100, sandbox.Is(ErrInsufficientFunds, sandbox.Halt))
+//  payments.Register(0, sandbox.As[*net.OpError](sandbox.Retry), sandbox.Declared())
+//
+//  how := retry.How{
+//  	strategy.Limit(5),
+//  	payments.Strategy(sandbox.Stop),
+//  	strategy.Backoff(backoff.Exponential(10*time.Millisecond, 2)),
+//  }
+//
+type Classifier struct {
+	// Clock is the source of time for the Strategy delays.
+	// The default is the clock.System.
+	Clock clock.Clock
+
+	mu    sync.RWMutex
+	rules []prioritized
+}
+
+type prioritized struct {
+	priority int
+	rule     Rule
+}
+
+// Register adds the rules with the given priority.
+func (classifier *Classifier) Register(priority int, rules ...Rule) {
+	classifier.mu.Lock()
+	defer classifier.mu.Unlock()
+
+	for _, rule := range rules {
+		classifier.rules = append(classifier.rules, prioritized{priority, rule})
+	}
+	sort.SliceStable(classifier.rules, func(i, j int) bool {
+		return classifier.rules[i].priority > classifier.rules[j].priority
+	})
+}
+
+// Classify returns the Outcome of the first matched rule.
+// It returns false if the error is nil or not matched by any rule.
+func (classifier *Classifier) Classify(err error) (Outcome, bool) {
+	if err == nil {
+		return Halt, false
+	}
+
+	classifier.mu.RLock()
+	defer classifier.mu.RUnlock()
+
+	for _, current := range classifier.rules {
+		if outcome, matched := current.rule(err); matched {
+			return outcome, true
+		}
+	}
+	return Halt, false
+}
+
+// Handler creates an error Handler for CheckError that returns true
+// if an error is classified as retriable.
+// The Handler returns the defaults if an error is not classified.
+func (classifier *Classifier) Handler(defaults bool) func(error) bool {
+	return func(err error) bool {
+		outcome, matched := classifier.Classify(err)
+		if !matched {
+			return defaults
+		}
+		return outcome.Retriable()
+	}
+}
+
+// Strategy creates a Strategy that classifies an error and waits
+// the Outcome delay before the next attempt. Inside the retry process,
+// it classifies the error returned by the action, not only its root cause.
+// The Strategy returns the defaults if an error is not classified.
+func (classifier *Classifier) Strategy(defaults bool) func(Breaker, uint, error) bool {
+	return func(breaker Breaker, attempt uint, err error) bool {
+		if attempt == 0 {
+			return true
+		}
+		// the root cause can be nil, e.g. for the *net.DNSError
+		if err = origin(breaker, err); err == nil {
+			return true
+		}
+		outcome, matched := classifier.Classify(err)
+		if !matched {
+			return defaults
+		}
+		if !outcome.Retriable() {
+			return false
+		}
+		if outcome.Delay() <= 0 {
+			return true
+		}
+
+		timer := classifier.clock().NewTimer(outcome.Delay())
+		defer timer.Stop()
+		select {
+		case <-breaker.Done():
+			return false
+		case <-timer.C():
+			return true
+		}
+	}
+}
+
+func (classifier *Classifier) clock() clock.Clock {
+	if classifier.Clock == nil {
+		return clock.System
+	}
+	return classifier.Clock
+}
@@ -0,0 +1,227 @@
+package sandbox_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/sandbox"
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/clock/clocktest"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestClassifier_Classify(t *testing.T) {
+	var classifier Classifier
+	classifier.Register(0,
+		Is(io.ErrUnexpectedEOF, Retry),
+		As[*net.DNSError](RetryAfter(time.Second)),
+		Pattern(regexp.MustCompile(`(?i)too many connections`), RetryAfter(time.Minute)),
+		Declared(),
+	)
+	classifier.Register(10, When(func(err error) bool { return errors.Is(err, io.EOF) }, Halt))
+	classifier.Register(-10, When(func(error) bool { return true }, Retry))
+	classifier.Register(10, AsFunc(func(err *net.OpError) Outcome {
+		if err.Op == "dial" {
+			return RetryAfter(time.Millisecond)
+		}
+		return Halt
+	}))
+
+	tests := map[string]struct {
+		error    error
+		expected Outcome
+		matched  bool
+	}{
+		"nil error": {
+			nil, Halt, false,
+		},
+		"by target": {
+			fmt.Errorf("read: %w", io.ErrUnexpectedEOF), Retry, true,
+		},
+		"by type": {
+			fmt.Errorf("lookup: %w", &net.DNSError{}), RetryAfter(time.Second), true,
+		},
+		"by message": {
+			errors.New("Too many connections"), RetryAfter(time.Minute), true,
+		},
+		"by declaration": {
+			retriable("no"), Halt, true,
+		},
+		"by priority": {
+			fmt.Errorf("%w: %v", io.EOF, retriable("yes")), Halt, true,
+		},
+		"by function": {
+			&net.OpError{Op: "dial", Err: errors.New("refused")}, RetryAfter(time.Millisecond), true,
+		},
+		"by function with equal priority": {
+			&net.OpError{Op: "read", Err: errors.New("reset")}, Halt, true,
+		},
+		"by fallback": {
+			errors.New("unknown"), Retry, true,
+		},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			outcome, matched := classifier.Classify(tc.error)
+			if outcome != tc.expected || matched != tc.matched {
+				t.Errorf("expected: %+v (%v), obtained: %+v (%v)", tc.expected, tc.matched, outcome, matched)
+			}
+		})
+	}
+}
+
+func TestClassifier_Handler(t *testing.T) {
+	var classifier Classifier
+	classifier.Register(0, Is(io.EOF, Halt), Is(io.ErrUnexpectedEOF, RetryAfter(time.Hour)))
+
+	tests := map[string]struct {
+		error    error
+		defaults bool
+		expected bool
+	}{
+		"halt":               {io.EOF, Skip, false},
+		"retry after":        {io.ErrUnexpectedEOF, Stop, true},
+		"unknown with skip":  {io.ErrClosedPipe, Skip, true},
+		"unknown with stop":  {io.ErrClosedPipe, Stop, false},
+		"declared retriable": {retriable("yes"), Stop, true},
+		"declared permanent": {retriable("no"), Skip, false},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			strategy := CheckError(classifier.Handler(tc.defaults))
+			if obtained := strategy(context.TODO(), 1, tc.error); obtained != tc.expected {
+				t.Errorf("expected: %v, obtained: %v", tc.expected, obtained)
+			}
+		})
+	}
+}
+
+func TestClassifier_Strategy(t *testing.T) {
+	fake := clocktest.New(time.Now())
+	classifier := Classifier{Clock: fake}
+	classifier.Register(0,
+		Is(io.EOF, Halt),
+		Is(io.ErrUnexpectedEOF, Retry),
+		Is(io.ErrShortWrite, RetryAfter(time.Minute)),
+	)
+
+	tests := map[string]struct {
+		attempt  uint
+		error    error
+		defaults bool
+		expected bool
+	}{
+		"first attempt": {0, io.EOF, Stop, true},
+		"success":       {1, nil, Stop, true},
+		"halt":          {1, io.EOF, Skip, false},
+		"retry":         {1, io.ErrUnexpectedEOF, Stop, true},
+		"unknown":       {1, io.ErrClosedPipe, Skip, true},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			if obtained := classifier.Strategy(tc.defaults)(context.TODO(), tc.attempt, tc.error); obtained != tc.expected {
+				t.Errorf("expected: %v, obtained: %v", tc.expected, obtained)
+			}
+		})
+	}
+
+	t.Run("retry after", func(t *testing.T) {
+		result := make(chan bool)
+		go func() { result <- classifier.Strategy(Stop)(context.TODO(), 1, io.ErrShortWrite) }()
+
+		fake.BlockUntil(1)
+		fake.Advance(time.Minute - time.Nanosecond)
+		select {
+		case <-result:
+			t.Fatal("strategy does not wait")
+		default:
+		}
+		fake.Advance(time.Nanosecond)
+		if !<-result {
+			t.Error("expected: true, obtained: false")
+		}
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		result := make(chan bool)
+		go func() { result <- classifier.Strategy(Stop)(ctx, 1, io.ErrShortWrite) }()
+
+		fake.BlockUntil(1)
+		cancel()
+		if <-result {
+			t.Error("strategy expected to be interrupted by the breaker")
+		}
+	})
+}
+
+func TestClassifier_Do(t *testing.T) {
+	var classifier Classifier
+	classifier.Register(0,
+		As[*net.OpError](Retry),
+		As[*net.DNSError](Halt),
+	)
+
+	tests := map[string]struct {
+		error    error
+		expected int
+	}{
+		"wrapping error": {
+			&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+			3,
+		},
+		"without root cause": {&net.DNSError{Err: "no such host", IsNotFound: true}, 1},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			action := func(context.Context) error {
+				calls++
+				return tc.error
+			}
+			err := retry.Do(context.Background(), action, strategy.Limit(3), classifier.Strategy(Skip))
+			if !errors.Is(err, retry.ErrExhausted) {
+				t.Errorf("unexpected error: %#v", err)
+			}
+			if calls != tc.expected {
+				t.Errorf("expected: %d, obtained: %d", tc.expected, calls)
+			}
+		})
+	}
+}
+
+func TestClassifier_Concurrency(t *testing.T) {
+	var (
+		classifier Classifier
+		wg         sync.WaitGroup
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(priority int) {
+			defer wg.Done()
+			classifier.Register(priority, Is(io.EOF, Retry))
+		}(i)
+		go func() {
+			defer wg.Done()
+			classifier.Classify(io.EOF)
+		}()
+	}
+	wg.Wait()
+
+	if outcome, matched := classifier.Classify(io.EOF); !matched || outcome != Retry {
+		t.Errorf("unexpected outcome: %+v (%v)", outcome, matched)
+	}
+}
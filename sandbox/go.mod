@@ -1,6 +1,6 @@
 module github.com/kamilsk/retry/sandbox
 
-go 1.11
+go 1.18
 
 require github.com/kamilsk/retry/v5 v5.0.0-rc8
 
@@ -0,0 +1,129 @@
+package sandbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kamilsk/retry/v5/transport"
+)
+
+// DefaultBodyLimit is the default size of the response body snippet.
+const DefaultBodyLimit = 512
+
+// HTTPError is an error carrying an unsuccessful HTTP response.
+//
+// It implements the Retriable method, so CheckError recognizes it,
+// and the RetryAfter method, so strategy.RetryAfter waits the time
+// requested by the server.
+type HTTPError struct {
+	// StatusCode is the response status code.
+	StatusCode int
+	// Header is the response header.
+	Header http.Header
+	// Body is the beginning of the response body.
+	Body []byte
+
+	retriable bool
+}
+
+// Error returns a string representation of an error.
+func (err *HTTPError) Error() string {
+	msg := fmt.Sprintf("sandbox: http status %d %s", err.StatusCode, http.StatusText(err.StatusCode))
+	if body := bytes.TrimSpace(err.Body); len(body) > 0 {
+		msg += ": " + string(body)
+	}
+	return msg
+}
+
+// Retriable reports whether the status code is classified as retriable.
+func (err *HTTPError) Retriable() bool { return err.retriable }
+
+// RetryAfter returns the time to wait requested by the Retry-After header
+// in the delta-seconds or HTTP-date form, or zero if it is absent.
+func (err *HTTPError) RetryAfter() time.Duration {
+	wait, _ := transport.ParseRetryAfter(err.Header.Get("Retry-After"), time.Now())
+	return wait
+}
+
+// CheckResponse returns an HTTPError if the response status code
+// is not successful, see HTTPClassifier.
+func CheckResponse(resp *http.Response) error {
+	return HTTPClassifier{}.Check(resp)
+}
+
+// HTTPClassifier converts HTTP responses into errors and classifies them.
+//
+//  classifier := sandbox.HTTPClassifier{Statuses: []int{http.StatusServiceUnavailable}}
+//  action := func(ctx context.Context) error {
+//  	resp, err := client.Do(req.WithContext(ctx))
+//  	if err != nil {
+//  		return err
+//  	}
+//  	defer resp.Body.Close()
+//  	return classifier.Check(resp)
+//  }
+//
+type HTTPClassifier struct {
+	// Statuses are retriable status codes.
+	// The default is the transport.RetriableStatuses.
+	Statuses []int
+	// BodyLimit is the size of the response body snippet.
+	// The default is the DefaultBodyLimit.
+	BodyLimit int64
+}
+
+// Check returns an HTTPError if the response status code is 400 or greater.
+// It reads the body snippet, but doesn't close the body.
+func (classifier HTTPClassifier) Check(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	limit := classifier.BodyLimit
+	if limit == 0 {
+		limit = DefaultBodyLimit
+	}
+	var body []byte
+	if resp.Body != nil && limit > 0 {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, limit))
+	}
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		retriable:  classifier.retriable(resp.StatusCode),
+	}
+}
+
+// Rule creates a Rule for the Classifier that matches an HTTPError
+// and classifies it by the Statuses regardless of how the error was created.
+// A retriable error is retried after the time requested by the server.
+func (classifier HTTPClassifier) Rule() Rule {
+	return func(err error) (Outcome, bool) {
+		var target *HTTPError
+		if !errors.As(err, &target) {
+			return Halt, false
+		}
+		if !classifier.retriable(target.StatusCode) {
+			return Halt, true
+		}
+		return RetryAfter(target.RetryAfter()), true
+	}
+}
+
+func (classifier HTTPClassifier) retriable(code int) bool {
+	statuses := classifier.Statuses
+	if statuses == nil {
+		statuses = transport.RetriableStatuses
+	}
+	for _, status := range statuses {
+		if status == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package sandbox_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/sandbox"
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/backoff"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestHTTPClassifier_Check(t *testing.T) {
+	tests := map[string]struct {
+		classifier HTTPClassifier
+		status     int
+		retriable  bool
+	}{
+		"ok":                  {HTTPClassifier{}, http.StatusOK, false},
+		"not modified":        {HTTPClassifier{}, http.StatusNotModified, false},
+		"bad request":         {HTTPClassifier{}, http.StatusBadRequest, false},
+		"request timeout":     {HTTPClassifier{}, http.StatusRequestTimeout, true},
+		"too early":           {HTTPClassifier{}, http.StatusTooEarly, true},
+		"too many requests":   {HTTPClassifier{}, http.StatusTooManyRequests, true},
+		"internal error":      {HTTPClassifier{}, http.StatusInternalServerError, true},
+		"not implemented":     {HTTPClassifier{}, http.StatusNotImplemented, false},
+		"bad gateway":         {HTTPClassifier{}, http.StatusBadGateway, true},
+		"service unavailable": {HTTPClassifier{}, http.StatusServiceUnavailable, true},
+		"gateway timeout":     {HTTPClassifier{}, http.StatusGatewayTimeout, true},
+		"configured":          {HTTPClassifier{Statuses: []int{http.StatusConflict}}, http.StatusConflict, true},
+		"not configured":      {HTTPClassifier{Statuses: []int{http.StatusConflict}}, http.StatusServiceUnavailable, false},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			resp := fetch(t, func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(tc.status)
+			})
+			err := tc.classifier.Check(resp)
+			if tc.status < http.StatusBadRequest {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var target *HTTPError
+			if !errors.As(err, &target) {
+				t.Fatalf("expected: %T, obtained: %#v", target, err)
+			}
+			if target.StatusCode != tc.status {
+				t.Errorf("expected: %d, obtained: %d", tc.status, target.StatusCode)
+			}
+			if obtained := target.Retriable(); obtained != tc.retriable {
+				t.Errorf("expected: %v, obtained: %v", tc.retriable, obtained)
+			}
+			if obtained := CheckError()(context.TODO(), 1, err); obtained != tc.retriable {
+				t.Errorf("expected: %v, obtained: %v", tc.retriable, obtained)
+			}
+		})
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	t.Run("body snippet", func(t *testing.T) {
+		body := strings.Repeat("x", 2*DefaultBodyLimit)
+		tests := map[string]struct {
+			limit    int64
+			expected int
+		}{
+			"default":  {0, DefaultBodyLimit},
+			"custom":   {10, 10},
+			"disabled": {-1, 0},
+			"larger":   {1 << 20, len(body)},
+		}
+		for name, test := range tests {
+			tc := test
+			t.Run(name, func(t *testing.T) {
+				resp := fetch(t, func(rw http.ResponseWriter, _ *http.Request) {
+					rw.WriteHeader(http.StatusBadGateway)
+					_, _ = fmt.Fprint(rw, body)
+				})
+				err := HTTPClassifier{BodyLimit: tc.limit}.Check(resp).(*HTTPError)
+				if obtained := len(err.Body); obtained != tc.expected {
+					t.Errorf("expected: %d, obtained: %d", tc.expected, obtained)
+				}
+			})
+		}
+	})
+
+	t.Run("message", func(t *testing.T) {
+		resp := fetch(t, func(rw http.ResponseWriter, _ *http.Request) {
+			rw.Header().Set("X-Request-Id", "42")
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = fmt.Fprintln(rw, "maintenance")
+		})
+		err := CheckResponse(resp).(*HTTPError)
+		if expected, obtained := "sandbox: http status 503 Service Unavailable: maintenance", err.Error(); obtained != expected {
+			t.Errorf("expected: %q, obtained: %q", expected, obtained)
+		}
+		if expected, obtained := "42", err.Header.Get("X-Request-Id"); obtained != expected {
+			t.Errorf("expected: %q, obtained: %q", expected, obtained)
+		}
+		if expected, obtained := "sandbox: http status 404 Not Found", (&HTTPError{StatusCode: 404}).Error(); obtained != expected {
+			t.Errorf("expected: %q, obtained: %q", expected, obtained)
+		}
+	})
+
+	t.Run("retry after", func(t *testing.T) {
+		tests := map[string]struct {
+			header string
+			min    time.Duration
+			max    time.Duration
+		}{
+			"absent":        {"", 0, 0},
+			"invalid":       {"soon", 0, 0},
+			"delta seconds": {"3", 3 * time.Second, 3 * time.Second},
+			"http date":     {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), 59 * time.Minute, time.Hour},
+			"past date":     {time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0},
+		}
+		for name, test := range tests {
+			tc := test
+			t.Run(name, func(t *testing.T) {
+				resp := fetch(t, func(rw http.ResponseWriter, _ *http.Request) {
+					if tc.header != "" {
+						rw.Header().Set("Retry-After", tc.header)
+					}
+					rw.WriteHeader(http.StatusTooManyRequests)
+				})
+				obtained := CheckResponse(resp).(*HTTPError).RetryAfter()
+				if obtained < tc.min || obtained > tc.max {
+					t.Errorf("expected: [%v, %v], obtained: %v", tc.min, tc.max, obtained)
+				}
+			})
+		}
+	})
+}
+
+func TestHTTPClassifier_Rule(t *testing.T) {
+	var classifier Classifier
+	classifier.Register(0, HTTPClassifier{Statuses: []int{http.StatusServiceUnavailable}}.Rule())
+
+	tests := map[string]struct {
+		err       error
+		matched   bool
+		retriable bool
+		delay     time.Duration
+	}{
+		"other error": {errors.New("failure"), false, false, 0},
+		"configured": {
+			&HTTPError{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"2"}}},
+			true, true, 2 * time.Second,
+		},
+		"wrapped": {
+			fmt.Errorf("fetch: %w", &HTTPError{StatusCode: http.StatusServiceUnavailable}),
+			true, true, 0,
+		},
+		"not configured": {&HTTPError{StatusCode: http.StatusTooManyRequests}, true, false, 0},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			outcome, matched := classifier.Classify(tc.err)
+			if matched != tc.matched {
+				t.Errorf("expected: %v, obtained: %v", tc.matched, matched)
+			}
+			if outcome.Retriable() != tc.retriable {
+				t.Errorf("expected: %v, obtained: %v", tc.retriable, outcome.Retriable())
+			}
+			if outcome.Delay() != tc.delay {
+				t.Errorf("expected: %v, obtained: %v", tc.delay, outcome.Delay())
+			}
+		})
+	}
+}
+
+func TestHTTPError_Retry(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls < 3 {
+			rw.Header().Set("Retry-After", "0")
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	action := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return CheckResponse(resp)
+	}
+	how := retry.How{strategy.Limit(5), CheckError(), strategy.RetryAfter(backoff.Constant(time.Millisecond), time.Second)}
+	if err := retry.Do(context.Background(), action, how...); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected: %d, obtained: %d", 3, calls)
+	}
+}
+
+// helpers
+
+func fetch(t *testing.T, handler http.HandlerFunc) *http.Response {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
@@ -1,6 +1,10 @@
 package sandbox
 
-import "net"
+import (
+	"net"
+
+	"github.com/kamilsk/retry/v5"
+)
 
 const (
 	Skip = true
@@ -28,6 +32,9 @@ type ErrorHandler = func(error) bool
 
 // CheckError creates a Strategy that checks an error and returns
 // if an error is retriable or not. Otherwise, it returns the defaults.
+// Inside the retry process, the handlers receive the error returned
+// by the action, not only its root cause.
+// See Classifier to combine rules with explicit priorities.
 func CheckError(handlers ...func(error) bool) func(Breaker, uint, error) bool {
 	// equal to go.octolab.org/errors.Retriable
 	type retriable interface {
@@ -35,13 +42,14 @@ func CheckError(handlers ...func(error) bool) func(Breaker, uint, error) bool {
 		Retriable() bool // Is the error retriable?
 	}
 
-	return func(_ Breaker, _ uint, err error) bool {
-		if err == nil {
-			return true
-		}
+	return func(breaker Breaker, _ uint, err error) bool {
 		if err, is := err.(retriable); is {
 			return err.Retriable()
 		}
+		// the root cause can be nil, e.g. for the *net.DNSError
+		if err = origin(breaker, err); err == nil {
+			return true
+		}
 		for _, handle := range handlers {
 			if !handle(err) {
 				return false
@@ -54,6 +62,7 @@ func CheckError(handlers ...func(error) bool) func(Breaker, uint, error) bool {
 // NetworkError creates an error Handler that checks an error and returns true
 // if an error is the temporary network error.
 // The Handler returns the defaults if an error is not a network error.
+// It relies on the deprecated net.Error.Temporary, see TransientError.
 func NetworkError(defaults bool) func(error) bool {
 	return func(err error) bool {
 		if err, is := err.(net.Error); is {
@@ -62,3 +71,12 @@ func NetworkError(defaults bool) func(error) bool {
 		return defaults
 	}
 }
+
+// origin returns the error of the last attempt as the action returned it,
+// because the retry process passes only its root cause to strategies.
+func origin(breaker Breaker, err error) error {
+	if info, is := retry.Info(breaker); is && info.Err != nil {
+		return info.Err
+	}
+	return err
+}
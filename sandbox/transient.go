@@ -0,0 +1,89 @@
+package sandbox
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// TransientError creates an error Handler that checks an error and returns
+// true if an error is recognized as transient by one of ConnectionReset,
+// ConnectionRefused, BrokenPipe, TimedOut, TryAgain, HostUnreachable,
+// UnexpectedEOF, TLSHandshakeTimeout, or TemporaryDNS.
+// The Handler returns the defaults if an error is not recognized.
+//
+// Unlike NetworkError, it doesn't rely on the deprecated net.Error.Temporary.
+func TransientError(defaults bool) func(error) bool {
+	classes := []func(error) bool{
+		ConnectionReset,
+		ConnectionRefused,
+		BrokenPipe,
+		TimedOut,
+		TryAgain,
+		HostUnreachable,
+		UnexpectedEOF,
+		TLSHandshakeTimeout,
+		TemporaryDNS,
+	}
+	return func(err error) bool {
+		for _, class := range classes {
+			if class(err) {
+				return true
+			}
+		}
+		return defaults
+	}
+}
+
+// ConnectionReset reports whether the connection was reset by the peer.
+func ConnectionReset(err error) bool { return errno(err, syscall.ECONNRESET) }
+
+// ConnectionRefused reports whether the connection was refused by the peer.
+func ConnectionRefused(err error) bool { return errno(err, syscall.ECONNREFUSED) }
+
+// BrokenPipe reports whether the connection was closed by the peer
+// while writing to it.
+func BrokenPipe(err error) bool { return errno(err, syscall.EPIPE) }
+
+// TimedOut reports whether the connection timed out on the system level.
+func TimedOut(err error) bool { return errno(err, syscall.ETIMEDOUT) }
+
+// TryAgain reports whether the resource is temporarily unavailable.
+func TryAgain(err error) bool { return errno(err, syscall.EAGAIN, syscall.EWOULDBLOCK) }
+
+// HostUnreachable reports whether there is no route to the host.
+func HostUnreachable(err error) bool { return errno(err, syscall.EHOSTUNREACH) }
+
+// UnexpectedEOF reports whether the connection was closed in the middle
+// of a message.
+func UnexpectedEOF(err error) bool { return errors.Is(err, io.ErrUnexpectedEOF) }
+
+// TLSHandshakeTimeout reports whether the TLS handshake timed out,
+// e.g., by the http.Transport TLSHandshakeTimeout.
+func TLSHandshakeTimeout(err error) bool {
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout() &&
+		strings.Contains(err.Error(), "TLS handshake timeout")
+}
+
+// TemporaryDNS reports whether the DNS lookup failed temporarily,
+// e.g., the server misbehaved or did not respond in time.
+func TemporaryDNS(err error) bool {
+	var dns *net.DNSError
+	return errors.As(err, &dns) && (dns.IsTemporary || dns.IsTimeout)
+}
+
+func errno(err error, targets ...syscall.Errno) bool {
+	var code syscall.Errno
+	if !errors.As(err, &code) {
+		return false
+	}
+	for _, target := range targets {
+		if code == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,303 @@
+//go:build !windows && !plan9 && !js
+
+package sandbox_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/sandbox"
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestTransientError(t *testing.T) {
+	tests := map[string]struct {
+		trigger func(t *testing.T) error
+		class   func(error) bool
+	}{
+		"connection reset":      {resetConnection, ConnectionReset},
+		"connection refused":    {refuseConnection, ConnectionRefused},
+		"broken pipe":           {breakPipe, BrokenPipe},
+		"try again":             {readEmptySocket, TryAgain},
+		"unexpected eof":        {interruptMessage, UnexpectedEOF},
+		"tls handshake timeout": {stallHandshake, TLSHandshakeTimeout},
+		"temporary dns":         {failLookup, TemporaryDNS},
+		// can't be triggered on the loopback interface
+		"timed out": {
+			func(*testing.T) error {
+				return &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ETIMEDOUT)}
+			},
+			TimedOut,
+		},
+		"host unreachable": {
+			func(*testing.T) error {
+				return &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.EHOSTUNREACH)}
+			},
+			HostUnreachable,
+		},
+	}
+	classes := map[string]func(error) bool{}
+	for name, test := range tests {
+		classes[name] = test.class
+	}
+
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			err := tc.trigger(t)
+			if err == nil {
+				t.Fatal("error expected")
+			}
+			if !tc.class(err) {
+				t.Errorf("error %#v is not recognized", err)
+			}
+			if !TransientError(Stop)(err) {
+				t.Errorf("error %v expected to be transient", err)
+			}
+			if !CheckError(TransientError(Stop))(context.TODO(), 1, err) {
+				t.Errorf("error %v expected to be retried", err)
+			}
+			for other, class := range classes {
+				if other != name && class(err) {
+					t.Errorf("error %v is unexpectedly recognized as %s", err, other)
+				}
+			}
+		})
+	}
+
+	t.Run("permanent", func(t *testing.T) {
+		for _, err := range []error{
+			errors.New("permanent"),
+			io.EOF,
+			&net.DNSError{Err: "no such host", IsNotFound: true},
+			&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.EACCES)},
+		} {
+			if TransientError(Stop)(err) || !TransientError(Skip)(err) {
+				t.Errorf("error %v is not expected to be recognized", err)
+			}
+		}
+	})
+}
+
+func TestTransientError_Do(t *testing.T) {
+	tests := map[string]struct {
+		trigger  func(t *testing.T) error
+		expected int
+	}{
+		"connection reset":      {resetConnection, 2},
+		"connection refused":    {refuseConnection, 2},
+		"broken pipe":           {breakPipe, 2},
+		"try again":             {readEmptySocket, 2},
+		"unexpected eof":        {interruptMessage, 2},
+		"tls handshake timeout": {stallHandshake, 2},
+		"temporary dns":         {failLookup, 2},
+		"permanent dns":         {missLookup, 1},
+	}
+	for name, test := range tests {
+		tc := test
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			action := func(context.Context) error {
+				calls++
+				return tc.trigger(t)
+			}
+			err := retry.Do(context.Background(), action, strategy.Limit(2), CheckError(TransientError(Stop)))
+			if !errors.Is(err, retry.ErrExhausted) {
+				t.Errorf("unexpected error: %#v", err)
+			}
+			if calls != tc.expected {
+				t.Errorf("expected: %d, obtained: %d", tc.expected, calls)
+			}
+		})
+	}
+}
+
+// helpers
+
+func listen(t *testing.T, serve func(net.Conn)) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func reset(conn net.Conn) {
+	_ = conn.(*net.TCPConn).SetLinger(0)
+	_ = conn.Close()
+}
+
+func resetConnection(t *testing.T) error {
+	conn, err := net.Dial("tcp", listen(t, reset))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	return err
+}
+
+func refuseConnection(t *testing.T) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	_, err = net.Dial("tcp", address)
+	return err
+}
+
+func breakPipe(t *testing.T) error {
+	socket := filepath.Join(t.TempDir(), "socket")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer listener.Close()
+	accepted := make(chan struct{})
+	go func() {
+		defer close(accepted)
+		if conn, err := listener.Accept(); err == nil {
+			_ = conn.Close()
+		}
+	}()
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	<-accepted
+
+	for i := 0; i < 100; i++ {
+		if _, err = conn.Write([]byte("ping")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readEmptySocket(t *testing.T) error {
+	conn, err := net.Dial("tcp", listen(t, func(conn net.Conn) {
+		time.Sleep(time.Second)
+		_ = conn.Close()
+	}))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	raw, err := conn.(*net.TCPConn).SyscallConn()
+	if err != nil {
+		return err
+	}
+	var failure error
+	if err := raw.Read(func(fd uintptr) bool {
+		_, failure = syscall.Read(int(fd), make([]byte, 1))
+		return true
+	}); err != nil {
+		return err
+	}
+	return &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", failure)}
+}
+
+func interruptMessage(t *testing.T) error {
+	address := listen(t, func(conn net.Conn) {
+		_, _ = io.WriteString(conn, "HTTP/1.1 200 OK\r\nContent-Length: 10\r\n\r\nhalf")
+		_ = conn.Close()
+	})
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, _ = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.ReadAll(resp.Body)
+	return err
+}
+
+func stallHandshake(t *testing.T) error {
+	address := listen(t, func(conn net.Conn) {
+		time.Sleep(time.Second)
+		_ = conn.Close()
+	})
+	client := http.Client{Transport: &http.Transport{TLSHandshakeTimeout: 20 * time.Millisecond}}
+	resp, err := client.Get("https://" + address)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	return err
+}
+
+func failLookup(t *testing.T) error { return lookup(t, 2) } // SERVFAIL
+
+func missLookup(t *testing.T) error { return lookup(t, 3) } // NXDOMAIN
+
+func lookup(t *testing.T, code byte) error {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := server.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < 12 {
+				continue
+			}
+			// answer the query with the response code
+			answer := append([]byte{}, buf[:n]...)
+			answer[2] |= 0x80
+			answer[3] = 0x80 | code
+			_, _ = server.WriteTo(answer, addr)
+		}
+	}()
+
+	resolver := net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "udp", server.LocalAddr().String())
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = resolver.LookupHost(ctx, "retry.test.")
+	return err
+}
@@ -0,0 +1,115 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteTable writes the report as an aligned text table.
+//
+//  attempt  expected  delay min  delay p50  ...  total p99  total max
+//  1        100ms     0s         49.8ms     ...  99ms       99.9ms
+//
+func (report Report) WriteTable(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, strings.Join(report.header("delay ", "total ", ""), "\t"))
+	for _, row := range report.Rows {
+		fmt.Fprintln(table, strings.Join(row.record(time.Duration.String), "\t"))
+	}
+	return table.Flush()
+}
+
+// WriteCSV writes the report in CSV format with durations in nanoseconds.
+func (report Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(report.header("delay_", "total_", "_ns")); err != nil {
+		return err
+	}
+	for _, row := range report.Rows {
+		record := row.record(func(duration time.Duration) string {
+			return strconv.FormatInt(int64(duration), 10)
+		})
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteHistogram writes an ASCII histogram of delays before the attempt
+// using the given number of buckets.
+//
+//  [0s, 10ms)     ########################################  1012
+//  [10ms, 20ms)   #######################################    987
+//
+func (report Report) WriteHistogram(w io.Writer, attempt uint, buckets int) error {
+	if attempt == 0 || int(attempt) > len(report.delays) {
+		return fmt.Errorf("simulation: attempt must be in [1, %d], obtained %d", len(report.delays), attempt)
+	}
+	if buckets <= 0 {
+		return fmt.Errorf("simulation: buckets must be positive, obtained %d", buckets)
+	}
+
+	const width = 40
+	stats := report.Rows[attempt-1].Delay
+	step := (stats.Max - stats.Min) / time.Duration(buckets)
+	if step <= 0 {
+		step, buckets = 1, 1
+	}
+	counts := make([]int, buckets)
+	for _, delay := range report.delays[attempt-1] {
+		i := int((delay - stats.Min) / step)
+		if i >= buckets {
+			i = buckets - 1
+		}
+		counts[i]++
+	}
+	highest := 0
+	for _, count := range counts {
+		if count > highest {
+			highest = count
+		}
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, count := range counts {
+		from := stats.Min + time.Duration(i)*step
+		to, closing := from+step, ")"
+		if i == buckets-1 {
+			to, closing = stats.Max, "]"
+		}
+		bar := strings.Repeat("#", count*width/highest)
+		fmt.Fprintf(table, "[%s, %s%s\t%-*s\t%d\n", from, to, closing, width, bar, count)
+	}
+	return table.Flush()
+}
+
+func (report Report) header(delay, total, unit string) []string {
+	header := []string{"attempt", "expected" + unit}
+	for _, prefix := range []string{delay, total} {
+		header = append(header, prefix+"min"+unit, prefix+"mean"+unit)
+		for _, percentile := range report.Percentiles {
+			header = append(header, prefix+"p"+strconv.FormatFloat(percentile, 'f', -1, 64)+unit)
+		}
+		header = append(header, prefix+"max"+unit)
+	}
+	return header
+}
+
+func (row Row) record(format func(time.Duration) string) []string {
+	record := []string{strconv.FormatUint(uint64(row.Attempt), 10), format(row.Expected)}
+	for _, stats := range []Stats{row.Delay, row.Total} {
+		record = append(record, format(stats.Min), format(stats.Mean))
+		for _, percentile := range stats.Percentiles {
+			record = append(record, format(percentile))
+		}
+		record = append(record, format(stats.Max))
+	}
+	return record
+}
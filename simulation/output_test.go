@@ -0,0 +1,119 @@
+package simulation_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kamilsk/retry/v5/backoff"
+	"github.com/kamilsk/retry/v5/jitter"
+	. "github.com/kamilsk/retry/v5/simulation"
+)
+
+func TestReport_WriteTable(t *testing.T) {
+	report, err := Run(Config{Algorithm: backoff.Linear(time.Second), Limit: 3, Samples: 1, Percentiles: []float64{99.9}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteTable(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"attempt  expected  delay min  delay mean  delay p99.9  delay max  total min  total mean  total p99.9  total max",
+		"1        1s        1s         1s          1s           1s         1s         1s          1s           1s",
+		"2        2s        2s         2s          2s           2s         3s         3s          3s           3s",
+	}
+	if obtained := strings.Split(strings.TrimSpace(buf.String()), "\n"); !reflect.DeepEqual(obtained, expected) {
+		t.Errorf("expected: %q, obtained: %q", expected, obtained)
+	}
+}
+
+func TestReport_WriteCSV(t *testing.T) {
+	report, err := Run(Config{Algorithm: backoff.Linear(time.Millisecond), Limit: 3, Samples: 1, Percentiles: []float64{50}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{
+		{"attempt", "expected_ns", "delay_min_ns", "delay_mean_ns", "delay_p50_ns", "delay_max_ns",
+			"total_min_ns", "total_mean_ns", "total_p50_ns", "total_max_ns"},
+		{"1", "1000000", "1000000", "1000000", "1000000", "1000000", "1000000", "1000000", "1000000", "1000000"},
+		{"2", "2000000", "2000000", "2000000", "2000000", "2000000", "3000000", "3000000", "3000000", "3000000"},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected: %v, obtained: %v", expected, records)
+	}
+}
+
+func TestReport_WriteHistogram(t *testing.T) {
+	const samples, buckets = 1000, 10
+
+	report, err := Run(Config{
+		Algorithm: backoff.Constant(time.Second),
+		Jitter: func(generator jitter.Generator) jitter.Transformation {
+			return jitter.Equal(generator)
+		},
+		Limit:   2,
+		Samples: samples,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteHistogram(&buf, 1, buckets); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != buckets {
+		t.Fatalf("expected: %d, obtained: %d", buckets, len(lines))
+	}
+	total := 0
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		var count int
+		if _, err := fmt.Sscan(fields[len(fields)-1], &count); err != nil {
+			t.Fatalf("unexpected line %q: %v", line, err)
+		}
+		total += count
+	}
+	if total != samples {
+		t.Errorf("expected: %d, obtained: %d", samples, total)
+	}
+	if !strings.HasPrefix(lines[0], "[500") || !strings.Contains(lines[buckets-1], "]") {
+		t.Errorf("unexpected bounds: %q", lines)
+	}
+
+	t.Run("constant", func(t *testing.T) {
+		report, _ := Run(Config{Algorithm: backoff.Constant(time.Second), Limit: 2, Samples: 5})
+		var buf bytes.Buffer
+		if err := report.WriteHistogram(&buf, 1, buckets); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 1 {
+			t.Errorf("expected a single bucket, obtained: %q", lines)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, args := range [][2]int{{0, buckets}, {2, buckets}, {1, 0}} {
+			if err := report.WriteHistogram(&buf, uint(args[0]), args[1]); err == nil {
+				t.Errorf("error expected for %v", args)
+			}
+		}
+	})
+}
@@ -0,0 +1,189 @@
+// Package simulation provides a way to preview what a backoff policy
+// actually does before shipping it: delays per attempt and the time
+// to give up, with jitter applied by Monte Carlo sampling.
+//
+//  report, err := simulation.Run(simulation.Config{
+//  	Algorithm: backoff.Exponential(100*time.Millisecond, 2),
+//  	Jitter:    func(generator jitter.Generator) jitter.Transformation {
+//  		return jitter.Full(generator)
+//  	},
+//  	Limit: 5,
+//  })
+//  if err != nil {
+//  	return err
+//  }
+//  return report.WriteTable(os.Stdout)
+//
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/kamilsk/retry/v5/backoff"
+	"github.com/kamilsk/retry/v5/jitter"
+)
+
+// Config defines a simulation.
+type Config struct {
+	// Algorithm calculates a delay before the attempt.
+	Algorithm backoff.Algorithm
+	// Jitter creates a Transformation of delays from the seeded generator.
+	// Delays are not transformed if it is nil.
+	Jitter func(generator jitter.Generator) jitter.Transformation
+	// Limit is the number of attempts, see strategy.Limit.
+	// The delays happen before each attempt after the first.
+	Limit uint
+	// Samples is the number of simulated retry processes.
+	// By default, it is 10000.
+	Samples int
+	// Seed initializes the generator used by the Jitter.
+	Seed int64
+	// Percentiles to report, each in (0, 100].
+	// By default, it is the 50th, 90th, and 99th percentiles.
+	Percentiles []float64
+}
+
+// Report contains the result of a simulation.
+type Report struct {
+	// Percentiles are the reported percentiles in the order of Stats.Percentiles.
+	Percentiles []float64
+	// Rows contains stats for each attempt that is made after a delay.
+	Rows []Row
+
+	delays [][]time.Duration
+}
+
+// Row contains stats for an attempt.
+type Row struct {
+	// Attempt is the number of the attempt, starting from 1.
+	Attempt uint
+	// Expected is the delay before the attempt without jitter.
+	Expected time.Duration
+	// Delay contains stats of the delay before the attempt.
+	Delay Stats
+	// Total contains stats of the sum of delays up to the attempt,
+	// i.e., the time to give up if all attempts fail.
+	Total Stats
+}
+
+// Stats describes a distribution of durations.
+type Stats struct {
+	Min, Mean, Max time.Duration
+	// Percentiles contains values of the Report.Percentiles.
+	Percentiles []time.Duration
+}
+
+// Run simulates the retry process defined by the config.
+func Run(config Config) (Report, error) {
+	config.defaults()
+	if err := config.validate(); err != nil {
+		return Report{}, err
+	}
+
+	transform := func(duration time.Duration) time.Duration { return duration }
+	if config.Jitter != nil {
+		transform = config.Jitter(rand.New(rand.NewSource(config.Seed)))
+	}
+
+	attempts := int(config.Limit) - 1
+	report := Report{
+		Percentiles: config.Percentiles,
+		Rows:        make([]Row, 0, attempts),
+		delays:      make([][]time.Duration, attempts),
+	}
+	totals := make([][]time.Duration, attempts)
+	for i := range report.delays {
+		report.delays[i] = make([]time.Duration, config.Samples)
+		totals[i] = make([]time.Duration, config.Samples)
+	}
+	for sample := 0; sample < config.Samples; sample++ {
+		var total time.Duration
+		for i := 0; i < attempts; i++ {
+			delay := transform(config.Algorithm(uint(i + 1)))
+			report.delays[i][sample] = delay
+			total = add(total, delay)
+			totals[i][sample] = total
+		}
+	}
+	for i := 0; i < attempts; i++ {
+		report.Rows = append(report.Rows, Row{
+			Attempt:  uint(i + 1),
+			Expected: config.Algorithm(uint(i + 1)),
+			Delay:    describe(report.delays[i], config.Percentiles),
+			Total:    describe(totals[i], config.Percentiles),
+		})
+	}
+	return report, nil
+}
+
+func (config *Config) defaults() {
+	if config.Samples == 0 {
+		config.Samples = 10000
+	}
+	if config.Percentiles == nil {
+		config.Percentiles = []float64{50, 90, 99}
+	}
+}
+
+func (config *Config) validate() error {
+	if config.Algorithm == nil {
+		return errors.New("simulation: algorithm is required")
+	}
+	if config.Limit < 2 {
+		return fmt.Errorf("simulation: limit must be at least 2 to make a delay, obtained %d", config.Limit)
+	}
+	if config.Samples < 0 {
+		return fmt.Errorf("simulation: samples must be positive, obtained %d", config.Samples)
+	}
+	for _, percentile := range config.Percentiles {
+		if !(percentile > 0 && percentile <= 100) {
+			return fmt.Errorf("simulation: percentile must be in (0, 100], obtained %v", percentile)
+		}
+	}
+	return nil
+}
+
+func describe(durations []time.Duration, percentiles []float64) Stats {
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum float64
+	for _, duration := range sorted {
+		sum += float64(duration)
+	}
+	stats := Stats{
+		Min:         sorted[0],
+		Mean:        sorted[len(sorted)-1],
+		Max:         sorted[len(sorted)-1],
+		Percentiles: make([]time.Duration, 0, len(percentiles)),
+	}
+	if mean := sum / float64(len(sorted)); mean < float64(stats.Max) {
+		stats.Mean = time.Duration(mean)
+	}
+	for _, percentile := range percentiles {
+		stats.Percentiles = append(stats.Percentiles, rank(sorted, percentile))
+	}
+	return stats
+}
+
+// rank returns the percentile of sorted durations by the nearest-rank method.
+func rank(sorted []time.Duration, percentile float64) time.Duration {
+	i := int(math.Ceil(percentile/100*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func add(a, b time.Duration) time.Duration {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
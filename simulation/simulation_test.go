@@ -0,0 +1,121 @@
+package simulation_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kamilsk/retry/v5/backoff"
+	"github.com/kamilsk/retry/v5/jitter"
+	. "github.com/kamilsk/retry/v5/simulation"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("without jitter", func(t *testing.T) {
+		report, err := Run(Config{Algorithm: backoff.Linear(time.Second), Limit: 4, Samples: 10})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if expected := []float64{50, 90, 99}; !reflect.DeepEqual(report.Percentiles, expected) {
+			t.Errorf("expected: %v, obtained: %v", expected, report.Percentiles)
+		}
+		expected := []Row{
+			{Attempt: 1, Expected: time.Second, Delay: constant(time.Second), Total: constant(time.Second)},
+			{Attempt: 2, Expected: 2 * time.Second, Delay: constant(2 * time.Second), Total: constant(3 * time.Second)},
+			{Attempt: 3, Expected: 3 * time.Second, Delay: constant(3 * time.Second), Total: constant(6 * time.Second)},
+		}
+		if !reflect.DeepEqual(report.Rows, expected) {
+			t.Errorf("expected: %+v, obtained: %+v", expected, report.Rows)
+		}
+	})
+
+	t.Run("with jitter", func(t *testing.T) {
+		config := Config{
+			Algorithm: backoff.BinaryExponential(time.Millisecond),
+			Jitter: func(generator jitter.Generator) jitter.Transformation {
+				return jitter.Full(generator)
+			},
+			Limit:       6,
+			Seed:        42,
+			Percentiles: []float64{1, 50, 100},
+		}
+		report, err := Run(config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(report.Rows) != 5 {
+			t.Fatalf("expected: %d, obtained: %d", 5, len(report.Rows))
+		}
+		for _, row := range report.Rows {
+			delay := row.Delay
+			if delay.Min < 0 || delay.Max >= row.Expected || delay.Percentiles[2] != delay.Max {
+				t.Errorf("unexpected delay stats of attempt %d: %+v", row.Attempt, delay)
+			}
+			if !(delay.Percentiles[0] <= delay.Percentiles[1] && delay.Percentiles[1] <= delay.Percentiles[2]) {
+				t.Errorf("unordered percentiles of attempt %d: %v", row.Attempt, delay.Percentiles)
+			}
+			if mean, median := delay.Mean, delay.Percentiles[1]; abs(mean-row.Expected/2) > row.Expected/20 ||
+				abs(median-row.Expected/2) > row.Expected/20 {
+				t.Errorf("unexpected center of attempt %d: mean %s, median %s", row.Attempt, mean, median)
+			}
+		}
+		if last := report.Rows[4].Total; last.Max >= 62*time.Millisecond || last.Min < 0 {
+			t.Errorf("unexpected total stats: %+v", last)
+		}
+
+		again, _ := Run(config)
+		if !reflect.DeepEqual(report.Rows, again.Rows) {
+			t.Error("report expected to be reproducible with the same seed")
+		}
+	})
+
+	t.Run("saturation", func(t *testing.T) {
+		report, err := Run(Config{Algorithm: backoff.Constant(backoff.MaxDuration), Limit: 3, Samples: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total := report.Rows[1].Total; total.Max != backoff.MaxDuration || total.Mean != backoff.MaxDuration {
+			t.Errorf("unexpected total stats: %+v", total)
+		}
+	})
+}
+
+func TestRun_Validation(t *testing.T) {
+	algorithm := backoff.Constant(time.Second)
+
+	tests := map[string]Config{
+		"no algorithm":       {Limit: 2},
+		"no delay":           {Algorithm: algorithm, Limit: 1},
+		"negative samples":   {Algorithm: algorithm, Limit: 2, Samples: -1},
+		"zero percentile":    {Algorithm: algorithm, Limit: 2, Percentiles: []float64{0}},
+		"too big percentile": {Algorithm: algorithm, Limit: 2, Percentiles: []float64{100.1}},
+	}
+	for name, test := range tests {
+		config := test
+		t.Run(name, func(t *testing.T) {
+			if _, err := Run(config); err == nil {
+				t.Error("error expected")
+			}
+		})
+	}
+}
+
+// helpers
+
+func abs(duration time.Duration) time.Duration {
+	if duration < 0 {
+		return -duration
+	}
+	return duration
+}
+
+func constant(duration time.Duration) Stats {
+	return Stats{
+		Min:         duration,
+		Mean:        duration,
+		Max:         duration,
+		Percentiles: []time.Duration{duration, duration, duration},
+	}
+}
@@ -0,0 +1,124 @@
+package strategy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kamilsk/retry/v5/clock"
+)
+
+// Budget is a retry budget shared across retry processes to prevent
+// load amplification. It allows retries as a fraction of the requests
+// made recently, with a minimum number of retries per second.
+//
+//  budget := &strategy.Budget{Ratio: 0.2, MinPerSecond: 10, TTL: 10 * time.Second}
+//
+//  how := retry.How{
+//  	strategy.Limit(5),
+//  	strategy.Backoff(backoff.Exponential(10*time.Millisecond, 2)),
+//  	budget.Allow,
+//  }
+//
+// The budget counts a request on the first attempt and withdraws a retry
+// on each following attempt, so it should be the last of strategies.
+// It is safe for concurrent use. Inspired by the Finagle RetryBudget
+// and the gRPC retry throttling.
+type Budget struct {
+	// Ratio is the fraction of requests that are allowed to be retried.
+	Ratio float64
+	// MinPerSecond is the number of retries per second allowed regardless
+	// of the number of requests.
+	MinPerSecond uint
+	// TTL is the time during which requests and retries are taken into account.
+	// The default is 10 seconds.
+	TTL time.Duration
+	// Clock is the source of time. The default is the clock.System.
+	Clock clock.Clock
+
+	mu      sync.Mutex
+	slots   []slot
+	current int
+	moment  time.Time
+	width   time.Duration
+}
+
+// Allow is a Strategy that counts requests and denies retries
+// once the budget is drained.
+func (budget *Budget) Allow(_ Breaker, attempt uint, _ error) bool {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+
+	budget.rotate(budget.now())
+	if attempt == 0 {
+		budget.slots[budget.current].requests++
+		return true
+	}
+	if budget.balance() < 1 {
+		return false
+	}
+	budget.slots[budget.current].retries++
+	return true
+}
+
+// Balance returns the number of retries available at the moment.
+func (budget *Budget) Balance() uint {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+
+	budget.rotate(budget.now())
+	if balance := budget.balance(); balance > 0 {
+		return uint(balance)
+	}
+	return 0
+}
+
+type slot struct {
+	requests, retries uint
+}
+
+const (
+	defaultTTL = 10 * time.Second
+	precision  = 10
+)
+
+func (budget *Budget) balance() float64 {
+	var requests, retries uint
+	for _, slot := range budget.slots {
+		requests += slot.requests
+		retries += slot.retries
+	}
+	reserve := float64(budget.MinPerSecond) * budget.ttl().Seconds()
+	return reserve + float64(requests)*budget.Ratio - float64(retries)
+}
+
+func (budget *Budget) rotate(now time.Time) {
+	if budget.slots == nil {
+		budget.slots = make([]slot, precision)
+		budget.moment, budget.width = now, budget.ttl()/precision+1
+		return
+	}
+
+	passed := now.Sub(budget.moment) / budget.width
+	if passed <= 0 {
+		return
+	}
+	budget.moment = budget.moment.Add(passed * budget.width)
+	for i := time.Duration(0); i < passed && i < precision; i++ {
+		budget.current = (budget.current + 1) % precision
+		budget.slots[budget.current] = slot{}
+	}
+}
+
+func (budget *Budget) now() time.Time {
+	if budget.Clock == nil {
+		return clock.System.Now()
+	}
+	return budget.Clock.Now()
+}
+
+func (budget *Budget) ttl() time.Duration {
+	if budget.TTL <= 0 {
+		return defaultTTL
+	}
+	return budget.TTL
+}
@@ -0,0 +1,91 @@
+package strategy_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kamilsk/retry/v5/clock/clocktest"
+	. "github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestBudget(t *testing.T) {
+	t.Run("ratio of requests", func(t *testing.T) {
+		budget := &Budget{Ratio: 0.5, TTL: time.Hour}
+		for i := 0; i < 10; i++ {
+			if !budget.Allow(breaker(), 0, nil) {
+				t.Fatal("request is denied")
+			}
+		}
+		if expected, obtained := uint(5), budget.Balance(); expected != obtained {
+			t.Errorf("expected: %d, obtained: %d", expected, obtained)
+		}
+		for i := 0; i < 5; i++ {
+			if !budget.Allow(breaker(), 1, nil) {
+				t.Fatalf("retry #%d is denied", i)
+			}
+		}
+		if budget.Allow(breaker(), 1, nil) {
+			t.Error("retry is allowed")
+		}
+	})
+
+	t.Run("minimum retries per second", func(t *testing.T) {
+		budget := &Budget{MinPerSecond: 2, TTL: 2 * time.Second}
+		for i := 0; i < 4; i++ {
+			if !budget.Allow(breaker(), uint(i+1), nil) {
+				t.Fatalf("retry #%d is denied", i)
+			}
+		}
+		if budget.Allow(breaker(), 1, nil) {
+			t.Error("retry is allowed")
+		}
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		fake := clocktest.New(time.Now())
+		budget := &Budget{Ratio: 1, TTL: 10 * time.Second, Clock: fake}
+		budget.Allow(breaker(), 0, nil)
+		if !budget.Allow(breaker(), 1, nil) {
+			t.Fatal("retry is denied")
+		}
+		if budget.Allow(breaker(), 1, nil) {
+			t.Fatal("retry is allowed")
+		}
+
+		fake.Advance(10 * time.Second)
+		if expected, obtained := uint(0), budget.Balance(); expected != obtained {
+			t.Errorf("expected: %d, obtained: %d", expected, obtained)
+		}
+		budget.Allow(breaker(), 0, nil)
+		if !budget.Allow(breaker(), 1, nil) {
+			t.Error("retry is denied")
+		}
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var (
+			budget  = &Budget{Ratio: 0.1, TTL: time.Hour}
+			retries int32
+			wg      sync.WaitGroup
+		)
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				budget.Allow(breaker(), 0, nil)
+				for attempt := uint(1); attempt < 5; attempt++ {
+					if budget.Allow(breaker(), attempt, nil) {
+						atomic.AddInt32(&retries, 1)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		if retries > 10 {
+			t.Errorf("too many retries: %d", retries)
+		}
+	})
+}
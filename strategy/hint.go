@@ -0,0 +1,97 @@
+package strategy
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kamilsk/retry/v5/clock"
+)
+
+// RetryAfter creates a Strategy that waits before each attempt, with a duration
+// provided by the last error as a server hint, e.g. by the Retry-After HTTP header
+// or the gRPC RetryInfo. If there is no hint, it uses the given backoff.Algorithm.
+//
+// The error provides the hint by implementing the interface
+//
+//  interface {
+//  	RetryAfter() time.Duration
+//  }
+//
+// The hint is capped by the given limit if it is positive, and the resulting
+// duration is capped by the time remaining until the breaker deadline.
+func RetryAfter(algorithm func(attempt uint) time.Duration, limit time.Duration) Strategy {
+	return WithClock(clock.System).RetryAfter(algorithm, limit)
+}
+
+// RetryAfterAtLeast creates a Strategy that waits before each attempt, with
+// a duration as defined by the given backoff.Algorithm, but not less than
+// the hint provided by the last error.
+//
+// See RetryAfter for details.
+func RetryAfterAtLeast(algorithm func(attempt uint) time.Duration, limit time.Duration) Strategy {
+	return WithClock(clock.System).RetryAfterAtLeast(algorithm, limit)
+}
+
+// RetryAfter creates a Strategy that waits before each attempt, with a duration
+// provided by the last error as a server hint, e.g. by the Retry-After HTTP header
+// or the gRPC RetryInfo. If there is no hint, it uses the given backoff.Algorithm.
+//
+// The breaker deadline is measured by the wall clock, because it's set
+// by the built-in context, while the wait is driven by the timing clock.
+//
+// See the package-level RetryAfter for details.
+func (timing Timing) RetryAfter(algorithm func(attempt uint) time.Duration, limit time.Duration) Strategy {
+	return timing.hinted(algorithm, limit, func(_, hint time.Duration) time.Duration {
+		return hint
+	})
+}
+
+// RetryAfterAtLeast creates a Strategy that waits before each attempt, with
+// a duration as defined by the given backoff.Algorithm, but not less than
+// the hint provided by the last error.
+//
+// See the package-level RetryAfter for details.
+func (timing Timing) RetryAfterAtLeast(algorithm func(attempt uint) time.Duration, limit time.Duration) Strategy {
+	return timing.hinted(algorithm, limit, func(computed, hint time.Duration) time.Duration {
+		if computed > hint {
+			return computed
+		}
+		return hint
+	})
+}
+
+func (timing Timing) hinted(
+	algorithm func(attempt uint) time.Duration,
+	limit time.Duration,
+	combine func(computed, hint time.Duration) time.Duration,
+) Strategy {
+	// e.g. an error built from the HTTP 429 or 503 response
+	type retryAfter interface {
+		error
+		RetryAfter() time.Duration
+	}
+
+	return func(breaker Breaker, attempt uint, err error) bool {
+		if attempt == 0 {
+			return true
+		}
+
+		duration := algorithm(attempt)
+		var hint retryAfter
+		if errors.As(err, &hint) {
+			suggested := hint.RetryAfter()
+			if limit > 0 && suggested > limit {
+				suggested = limit
+			}
+			duration = combine(duration, suggested)
+		}
+		if deadline, is := breaker.(interface{ Deadline() (time.Time, bool) }); is {
+			if at, has := deadline.Deadline(); has {
+				if remaining := time.Until(at); duration > remaining {
+					duration = remaining
+				}
+			}
+		}
+		return timing.sleep(breaker, duration)
+	}
+}
@@ -0,0 +1,118 @@
+package strategy_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kamilsk/retry/v5/clock/clocktest"
+	. "github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestRetryAfter(t *testing.T) {
+	algorithm := func(uint) time.Duration { return time.Minute }
+
+	tests := map[string]struct {
+		policy   func(Timing) Strategy
+		args     tuple
+		expected time.Duration
+	}{
+		"override without hint": {
+			func(timing Timing) Strategy { return timing.RetryAfter(algorithm, 0) },
+			tuple{breaker(), 1, errors.New("no hint")},
+			time.Minute,
+		},
+		"override with hint": {
+			func(timing Timing) Strategy { return timing.RetryAfter(algorithm, 0) },
+			tuple{breaker(), 1, hint(time.Second)},
+			time.Second,
+		},
+		"override with wrapped hint": {
+			func(timing Timing) Strategy { return timing.RetryAfter(algorithm, 0) },
+			tuple{breaker(), 1, fmt.Errorf("wrapped: %w", hint(time.Second))},
+			time.Second,
+		},
+		"override with capped hint": {
+			func(timing Timing) Strategy { return timing.RetryAfter(algorithm, time.Hour) },
+			tuple{breaker(), 1, hint(24 * time.Hour)},
+			time.Hour,
+		},
+		"floor with small hint": {
+			func(timing Timing) Strategy { return timing.RetryAfterAtLeast(algorithm, 0) },
+			tuple{breaker(), 1, hint(time.Second)},
+			time.Minute,
+		},
+		"floor with large hint": {
+			func(timing Timing) Strategy { return timing.RetryAfterAtLeast(algorithm, time.Hour) },
+			tuple{breaker(), 1, hint(2 * time.Hour)},
+			time.Hour,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := clocktest.New(time.Now())
+			policy := test.policy(WithClock(fake))
+
+			result := make(chan bool)
+			go func() { result <- policy(test.args.unpack()) }()
+
+			fake.BlockUntil(1)
+			fake.Advance(test.expected - time.Nanosecond)
+			select {
+			case <-result:
+				t.Fatal("strategy does not wait")
+			default:
+			}
+			fake.Advance(time.Nanosecond)
+			if !<-result {
+				t.Error("expected: true, obtained: false")
+			}
+		})
+	}
+
+	t.Run("first attempt", func(t *testing.T) {
+		if !RetryAfter(algorithm, 0)(breaker(), 0, hint(time.Hour)) {
+			t.Error("expected: true, obtained: false")
+		}
+	})
+
+	t.Run("capped by breaker deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		now := time.Now()
+		_ = RetryAfter(algorithm, 0)(ctx, 1, hint(time.Hour))
+		if time.Since(now) > time.Second {
+			t.Error("strategy waits too long")
+		}
+	})
+
+	t.Run("capped by breaker deadline on the wall clock", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+
+		fake := clocktest.New(time.Unix(0, 0))
+		result := make(chan bool)
+		go func() { result <- WithClock(fake).RetryAfter(algorithm, 0)(ctx, 1, hint(2*time.Hour)) }()
+
+		fake.BlockUntil(1)
+		fake.Advance(time.Hour)
+		select {
+		case obtained := <-result:
+			if !obtained {
+				t.Error("expected: true, obtained: false")
+			}
+		case <-time.After(time.Second):
+			t.Error("strategy waits too long")
+		}
+	})
+}
+
+// helpers
+
+type hint time.Duration
+
+func (hint hint) Error() string             { return "retry after " + hint.RetryAfter().String() }
+func (hint hint) RetryAfter() time.Duration { return time.Duration(hint) }
@@ -1,7 +1,13 @@
 // Package strategy provides a way to define how retry is performed.
 package strategy
 
-import "time"
+import (
+	"sync"
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/clock"
+)
 
 // A Breaker carries a cancellation signal to interrupt an action execution.
 //
@@ -36,16 +42,62 @@ func Limit(value uint) Strategy {
 // Delay creates a Strategy that waits the given duration
 // before the first attempt is made.
 func Delay(duration time.Duration) Strategy {
+	return WithClock(clock.System).Delay(duration)
+}
+
+// Wait creates a Strategy that waits the given durations for each attempt after
+// the first. If the number of attempts is greater than the number of durations
+// provided, then the strategy uses the last duration provided.
+func Wait(durations ...time.Duration) Strategy {
+	return WithClock(clock.System).Wait(durations...)
+}
+
+// Backoff creates a Strategy that waits before each attempt, with a duration as
+// defined by the given backoff.Algorithm.
+func Backoff(algorithm func(attempt uint) time.Duration) Strategy {
+	return WithClock(clock.System).Backoff(algorithm)
+}
+
+// BackoffWithJitter creates a Strategy that waits before each attempt, with a
+// duration as defined by the given backoff.Algorithm and jitter.Transformation.
+func BackoffWithJitter(
+	algorithm func(attempt uint) time.Duration,
+	transformation func(duration time.Duration) time.Duration,
+) Strategy {
+	return WithClock(clock.System).BackoffWithJitter(algorithm, transformation)
+}
+
+// StatefulBackoff creates a Strategy that waits before each attempt, with
+// a duration as defined by the backoff.Algorithm created by the given
+// backoff.Factory for each retry process.
+func StatefulBackoff(factory func() func(attempt uint) time.Duration) Strategy {
+	return WithClock(clock.System).StatefulBackoff(factory)
+}
+
+// Timing creates time-based strategies driven by a clock.
+type Timing struct {
+	clock clock.Clock
+}
+
+// WithClock returns a Timing that creates strategies driven by the given clock.
+//
+//  fake := clocktest.New(time.Now())
+//  how := retry.How{
+//  	strategy.Limit(5),
+//  	strategy.WithClock(fake).Backoff(backoff.Exponential(time.Second, 2)),
+//  }
+//
+func WithClock(clock clock.Clock) Timing {
+	return Timing{clock}
+}
+
+// Delay creates a Strategy that waits the given duration
+// before the first attempt is made.
+func (timing Timing) Delay(duration time.Duration) Strategy {
 	return func(breaker Breaker, attempt uint, _ error) bool {
 		keep := true
 		if attempt == 0 {
-			timer := time.NewTimer(duration)
-			select {
-			case <-timer.C:
-			case <-breaker.Done():
-				keep = false
-			}
-			stop(timer)
+			keep = timing.sleep(breaker, duration)
 		}
 		return keep
 	}
@@ -54,7 +106,7 @@ func Delay(duration time.Duration) Strategy {
 // Wait creates a Strategy that waits the given durations for each attempt after
 // the first. If the number of attempts is greater than the number of durations
 // provided, then the strategy uses the last duration provided.
-func Wait(durations ...time.Duration) Strategy {
+func (timing Timing) Wait(durations ...time.Duration) Strategy {
 	return func(breaker Breaker, attempt uint, _ error) bool {
 		keep := true
 		if attempt > 0 && len(durations) > 0 {
@@ -62,13 +114,7 @@ func Wait(durations ...time.Duration) Strategy {
 			if len(durations) <= durationIndex {
 				durationIndex = len(durations) - 1
 			}
-			timer := time.NewTimer(durations[durationIndex])
-			select {
-			case <-timer.C:
-			case <-breaker.Done():
-				keep = false
-			}
-			stop(timer)
+			keep = timing.sleep(breaker, durations[durationIndex])
 		}
 		return keep
 	}
@@ -76,37 +122,77 @@ func Wait(durations ...time.Duration) Strategy {
 
 // Backoff creates a Strategy that waits before each attempt, with a duration as
 // defined by the given backoff.Algorithm.
-func Backoff(algorithm func(attempt uint) time.Duration) Strategy {
-	return BackoffWithJitter(algorithm, func(duration time.Duration) time.Duration {
+func (timing Timing) Backoff(algorithm func(attempt uint) time.Duration) Strategy {
+	return timing.BackoffWithJitter(algorithm, func(duration time.Duration) time.Duration {
 		return duration
 	})
 }
 
 // BackoffWithJitter creates a Strategy that waits before each attempt, with a
 // duration as defined by the given backoff.Algorithm and jitter.Transformation.
-func BackoffWithJitter(
+func (timing Timing) BackoffWithJitter(
 	algorithm func(attempt uint) time.Duration,
 	transformation func(duration time.Duration) time.Duration,
 ) Strategy {
 	return func(breaker Breaker, attempt uint, _ error) bool {
 		keep := true
 		if attempt > 0 {
-			timer := time.NewTimer(transformation(algorithm(attempt)))
-			select {
-			case <-timer.C:
-			case <-breaker.Done():
-				keep = false
-			}
-			stop(timer)
+			keep = timing.sleep(breaker, transformation(algorithm(attempt)))
 		}
 		return keep
 	}
 }
 
-func stop(timer *time.Timer) {
+// StatefulBackoff creates a Strategy that waits before each attempt, with
+// a duration as defined by the backoff.Algorithm created by the given
+// backoff.Factory for each retry process.
+//
+// The backoff.Algorithm is bound to the retry process by retry.Local.
+// If the strategy is called outside of the retry process, it shares
+// the backoff.Algorithm between calls and recreates it on the first retry.
+func (timing Timing) StatefulBackoff(factory func() func(attempt uint) time.Duration) Strategy {
+	var (
+		key    = new(byte)
+		mu     sync.Mutex
+		shared func(attempt uint) time.Duration
+	)
+
+	return func(breaker Breaker, attempt uint, _ error) bool {
+		if attempt == 0 {
+			return true
+		}
+
+		if _, bound := retry.Info(breaker); bound {
+			algorithm, _ := retry.Local(breaker, key, factory)
+			return timing.sleep(breaker, algorithm(attempt))
+		}
+
+		mu.Lock()
+		if shared == nil || attempt == 1 {
+			shared = factory()
+		}
+		duration := shared(attempt)
+		mu.Unlock()
+		return timing.sleep(breaker, duration)
+	}
+}
+
+func (timing Timing) sleep(breaker Breaker, duration time.Duration) bool {
+	keep := true
+	timer := timing.clock.NewTimer(duration)
+	select {
+	case <-timer.C():
+	case <-breaker.Done():
+		keep = false
+	}
+	stop(timer)
+	return keep
+}
+
+func stop(timer clock.Timer) {
 	if !timer.Stop() {
 		select {
-		case <-timer.C:
+		case <-timer.C():
 		default:
 		}
 	}
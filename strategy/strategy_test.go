@@ -3,9 +3,13 @@ package strategy_test
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/clock/clocktest"
 	. "github.com/kamilsk/retry/v5/strategy"
 )
 
@@ -438,6 +442,135 @@ func TestBackoffWithJitter(t *testing.T) {
 	}
 }
 
+func TestWithClock(t *testing.T) {
+	fake := clocktest.New(time.Now())
+	timing := WithClock(fake)
+
+	tests := map[string]struct {
+		policy  Strategy
+		args    tuple
+		advance time.Duration
+	}{
+		"delay": {
+			timing.Delay(time.Hour),
+			tuple{breaker(), 0, nil},
+			time.Hour,
+		},
+		"wait": {
+			timing.Wait(time.Minute, time.Hour),
+			tuple{breaker(), 5, nil},
+			time.Hour,
+		},
+		"backoff": {
+			timing.Backoff(func(attempt uint) time.Duration { return time.Duration(attempt) * time.Hour }),
+			tuple{breaker(), 2, nil},
+			2 * time.Hour,
+		},
+		"backoff with jitter": {
+			timing.BackoffWithJitter(
+				func(uint) time.Duration { return time.Hour },
+				func(duration time.Duration) time.Duration { return duration / 2 },
+			),
+			tuple{breaker(), 1, nil},
+			time.Hour / 2,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := make(chan bool)
+			go func() { result <- test.policy(test.args.unpack()) }()
+
+			fake.BlockUntil(1)
+			fake.Advance(test.advance - time.Nanosecond)
+			select {
+			case <-result:
+				t.Fatal("strategy does not wait")
+			default:
+			}
+			fake.Advance(time.Nanosecond)
+			if !<-result {
+				t.Error("expected: true, obtained: false")
+			}
+			if fake.Sleepers() != 0 {
+				t.Error("unexpected sleepers")
+			}
+		})
+	}
+
+	t.Run("interrupted breaker", func(t *testing.T) {
+		if timing.Delay(time.Hour)(interrupted(), 0, nil) {
+			t.Error("expected: false, obtained: true")
+		}
+		if fake.Sleepers() != 0 {
+			t.Error("timer is not stopped")
+		}
+	})
+}
+
+func TestStatefulBackoff(t *testing.T) {
+	factory := func(created *int32) func() func(uint) time.Duration {
+		return func() func(uint) time.Duration {
+			atomic.AddInt32(created, 1)
+			var previous time.Duration
+			return func(uint) time.Duration {
+				previous += time.Millisecond
+				return previous
+			}
+		}
+	}
+
+	t.Run("per retry process", func(t *testing.T) {
+		var (
+			created int32
+			policy  = StatefulBackoff(factory(&created))
+			wg      sync.WaitGroup
+		)
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				now := time.Now()
+				_ = retry.Do(context.Background(), func(context.Context) error {
+					return errors.New("failure")
+				}, Limit(3), policy)
+				if time.Since(now) < 3*time.Millisecond {
+					t.Error("unexpected waiting time")
+				}
+			}()
+		}
+		wg.Wait()
+
+		if created != 3 {
+			t.Errorf("expected: %d, obtained: %d", 3, created)
+		}
+	})
+
+	t.Run("outside of the retry process", func(t *testing.T) {
+		var (
+			created int32
+			fake    = clocktest.New(time.Now())
+			policy  = WithClock(fake).StatefulBackoff(factory(&created))
+		)
+		if !policy(breaker(), 0, nil) {
+			t.Error("expected: true, obtained: false")
+		}
+		for attempt, duration := range []time.Duration{time.Millisecond, 2 * time.Millisecond, time.Millisecond} {
+			retry := uint(attempt%2 + 1)
+			result := make(chan bool)
+			go func() { result <- policy(breaker(), retry, nil) }()
+
+			fake.BlockUntil(1)
+			fake.Advance(duration)
+			if !<-result {
+				t.Error("expected: true, obtained: false")
+			}
+		}
+		if created != 2 {
+			t.Errorf("expected: %d, obtained: %d", 2, created)
+		}
+	})
+}
+
 // helpers
 
 func breaker() Breaker {
@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrAttemptTimeout is a sentinel to check that an attempt has exceeded
+// its own timeout while the breaker is still alive, see AttemptTimeout.
+const ErrAttemptTimeout Error = "retry: attempt timed out"
+
+// AttemptTimeout creates an Option that performs each attempt
+// with its own deadline derived from the breaker.
+//
+// If the attempt exceeds the timeout, its error is wrapped to be matched
+// by the ErrAttemptTimeout and the retry process continues, while
+// the breaker interruption still stops the process. Strategies receive
+// the wrapped error, so they can distinguish the attempt timeout
+// from other deadline errors.
+func AttemptTimeout(timeout time.Duration) Option {
+	return func(cfg *options) {
+		cfg.timeout = func(context.Context) time.Duration { return timeout }
+	}
+}
+
+// AttemptTimeoutRatio creates an Option that performs each attempt
+// with its own deadline derived from the breaker. The timeout is
+// the given fraction of time remaining until the breaker deadline.
+// It has no effect if the breaker has no deadline.
+//
+// See AttemptTimeout for details.
+func AttemptTimeoutRatio(ratio float64) Option {
+	return func(cfg *options) {
+		cfg.timeout = func(ctx context.Context) time.Duration {
+			deadline, is := ctx.Deadline()
+			if !is {
+				return 0
+			}
+			return time.Duration(float64(time.Until(deadline)) * ratio)
+		}
+	}
+}
+
+// timeoutError intentionally has no Unwrap method to stay visible
+// for strategies, but it still matches the wrapped error
+// by errors.Is and errors.As.
+type timeoutError struct{ error }
+
+func (err timeoutError) Error() string { return ErrAttemptTimeout.Error() + ": " + err.error.Error() }
+func (err timeoutError) Is(target error) bool {
+	return target == ErrAttemptTimeout || errors.Is(err.error, target)
+}
+func (err timeoutError) As(target interface{}) bool { return errors.As(err.error, target) }
+func (err timeoutError) Timeout() bool              { return true }
+
+func perform(ctx context.Context, action func(context.Context) error, timeout time.Duration) error {
+	if timeout <= 0 {
+		return action(ctx)
+	}
+
+	limited, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := action(limited)
+	if err != nil && limited.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		err = timeoutError{err}
+	}
+	return err
+}
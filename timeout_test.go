@@ -0,0 +1,119 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestAttemptTimeout(t *testing.T) {
+	hang := func(calls *int, succeed int) func(context.Context) error {
+		return func(ctx context.Context) error {
+			if *calls++; *calls >= succeed {
+				return nil
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}
+	}
+
+	t.Run("retry timed out attempt", func(t *testing.T) {
+		var calls int
+		ctx := With(breaker(), AttemptTimeout(time.Millisecond))
+		if err := Do(ctx, hang(&calls, 3), strategy.Limit(5)); err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if calls != 3 {
+			t.Errorf("expected: %d, obtained: %d", 3, calls)
+		}
+	})
+
+	t.Run("give up timed out attempts", func(t *testing.T) {
+		var calls int
+		ctx := With(breaker(), AttemptTimeout(time.Millisecond))
+		err := Do(ctx, hang(&calls, 10), strategy.Limit(2))
+		if !errors.Is(err, ErrAttemptTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if expected, obtained := "retry: gave up after 2 attempts: "+
+			"retry: attempt timed out: context deadline exceeded", err.Error(); expected != obtained {
+			t.Errorf("expected: %q, obtained: %q", expected, obtained)
+		}
+	})
+
+	t.Run("visible to strategies", func(t *testing.T) {
+		var (
+			calls    int
+			observed []error
+		)
+		observe := func(breaker Breaker, attempt uint, err error) bool {
+			if info, is := Info(breaker); is && attempt > 0 {
+				observed = append(observed, err, info.Cause)
+			}
+			return true
+		}
+		ctx := With(breaker(), AttemptTimeout(time.Millisecond))
+		if err := Do(ctx, hang(&calls, 2), observe); err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if len(observed) != 2 {
+			t.Fatalf("expected: %d, obtained: %d", 2, len(observed))
+		}
+		for _, err := range observed {
+			if !errors.Is(err, ErrAttemptTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("unexpected error: %#v", err)
+			}
+		}
+	})
+
+	t.Run("parent cancellation", func(t *testing.T) {
+		var calls int
+		parent, cancel := context.WithTimeout(context.TODO(), 5*time.Millisecond)
+		defer cancel()
+
+		ctx := With(parent, AttemptTimeout(time.Hour))
+		err := Do(ctx, hang(&calls, 10))
+		if !errors.Is(err, ErrCancelled) || errors.Is(err, ErrAttemptTimeout) {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected: %d, obtained: %d", 1, calls)
+		}
+	})
+
+	t.Run("fraction of remaining time", func(t *testing.T) {
+		var deadlines []time.Duration
+		parent, cancel := context.WithTimeout(context.TODO(), time.Hour)
+		defer cancel()
+
+		ctx := With(parent, AttemptTimeoutRatio(0.5))
+		action := func(ctx context.Context) error {
+			deadline, _ := ctx.Deadline()
+			deadlines = append(deadlines, time.Until(deadline))
+			return nil
+		}
+		if err := Do(ctx, action); err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+		if len(deadlines) != 1 || deadlines[0] > 30*time.Minute || deadlines[0] < 29*time.Minute {
+			t.Errorf("unexpected deadlines: %v", deadlines)
+		}
+	})
+
+	t.Run("fraction without deadline", func(t *testing.T) {
+		ctx := With(breaker(), AttemptTimeoutRatio(0.5))
+		action := func(ctx context.Context) error {
+			if _, is := ctx.Deadline(); is {
+				t.Error("unexpected deadline")
+			}
+			return nil
+		}
+		if err := Do(ctx, action); err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+	})
+}
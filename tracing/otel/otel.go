@@ -0,0 +1,177 @@
+// Package otel provides the OpenTelemetry instrumentation of the retry process.
+//
+// Each call produces a span with a child span per attempt. The context
+// handed to the action carries the attempt span, so the spans created
+// by the action are nested into it.
+//
+//  err := otel.Do(ctx, "fetch", action, how...)
+//
+package otel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+
+	stdotel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kamilsk/retry/v5"
+)
+
+// InstrumentationName is the name of the tracer.
+const InstrumentationName = "github.com/kamilsk/retry/tracing/otel"
+
+// Attribute keys recorded by the instrumentation.
+const (
+	// AttemptKey is the zero-based number of an attempt.
+	AttemptKey = attribute.Key("retry.attempt")
+	// DelayKey is the time in seconds spent by strategies before an attempt.
+	DelayKey = attribute.Key("retry.delay")
+	// AttemptsKey is the number of attempts made by the retry process.
+	AttemptsKey = attribute.Key("retry.attempts")
+	// OutcomeKey is one of "success", "exhausted", and "cancelled".
+	OutcomeKey = attribute.Key("retry.outcome")
+	// StoppedByKey is the name of the strategy that halted the retry process.
+	StoppedByKey = attribute.Key("retry.stopped_by")
+	// StoppedByIndexKey is the index of the strategy that halted the retry process.
+	StoppedByIndexKey = attribute.Key("retry.stopped_by.index")
+)
+
+// Do calls the retry.Do and traces it by the global tracer provider.
+func Do(
+	breaker retry.Breaker,
+	name string,
+	action func(context.Context) error,
+	strategies ...func(retry.Breaker, uint, error) bool,
+) error {
+	return Tracer{}.Do(breaker, name, action, strategies...)
+}
+
+// Go calls the retry.Go and traces it by the global tracer provider.
+func Go(
+	breaker retry.Breaker,
+	name string,
+	action func(context.Context) error,
+	strategies ...func(retry.Breaker, uint, error) bool,
+) error {
+	return Tracer{}.Go(breaker, name, action, strategies...)
+}
+
+// Tracer traces the retry process.
+type Tracer struct {
+	// Provider creates the tracer.
+	// By default, it is the global tracer provider.
+	Provider trace.TracerProvider
+}
+
+// Do calls the retry.Do and traces it.
+func (tracer Tracer) Do(
+	breaker retry.Breaker,
+	name string,
+	action func(context.Context) error,
+	strategies ...func(retry.Breaker, uint, error) bool,
+) error {
+	return tracer.trace(retry.Do, breaker, name, action, strategies)
+}
+
+// Go calls the retry.Go and traces it.
+func (tracer Tracer) Go(
+	breaker retry.Breaker,
+	name string,
+	action func(context.Context) error,
+	strategies ...func(retry.Breaker, uint, error) bool,
+) error {
+	return tracer.trace(retry.Go, breaker, name, action, strategies)
+}
+
+type runner = func(retry.Breaker, func(context.Context) error, ...func(retry.Breaker, uint, error) bool) error
+
+func (tracer Tracer) trace(
+	run runner,
+	breaker retry.Breaker,
+	name string,
+	action func(context.Context) error,
+	strategies []func(retry.Breaker, uint, error) bool,
+) error {
+	provider := tracer.Provider
+	if provider == nil {
+		provider = stdotel.GetTracerProvider()
+	}
+	t := provider.Tracer(InstrumentationName)
+
+	parent, is := breaker.(context.Context)
+	if !is {
+		parent = lite{context.Background(), breaker}
+	}
+	ctx, span := t.Start(parent, name)
+	defer span.End()
+
+	var made int64
+	attempt := func(ctx context.Context) error {
+		atomic.AddInt64(&made, 1)
+		info, _ := retry.Info(ctx)
+		ctx, child := t.Start(ctx, name+" attempt", trace.WithAttributes(
+			AttemptKey.Int64(int64(info.Number)),
+			DelayKey.Float64(info.Wait.Seconds()),
+		))
+		defer child.End()
+
+		err := action(ctx)
+		if err != nil {
+			child.RecordError(err)
+			child.SetStatus(codes.Error, err.Error())
+		}
+		return err
+	}
+
+	observed := make([]func(retry.Breaker, uint, error) bool, 0, len(strategies))
+	for i, strategy := range strategies {
+		i, strategy := i, strategy
+		observed = append(observed, func(breaker retry.Breaker, attempt uint, err error) bool {
+			should := strategy(breaker, attempt, err)
+			if !should {
+				span.SetAttributes(StoppedByKey.String(nameOf(strategy)), StoppedByIndexKey.Int(i))
+			}
+			return should
+		})
+	}
+
+	err := run(ctx, attempt, observed...)
+	span.SetAttributes(AttemptsKey.Int64(atomic.LoadInt64(&made)), OutcomeKey.String(outcome(err)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+type lite struct {
+	context.Context
+	breaker retry.Breaker
+}
+
+func (ctx lite) Done() <-chan struct{} { return ctx.breaker.Done() }
+func (ctx lite) Err() error            { return ctx.breaker.Err() }
+
+func nameOf(strategy func(retry.Breaker, uint, error) bool) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(strategy).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return "unknown"
+}
+
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, retry.ErrCancelled):
+		return "cancelled"
+	default:
+		return "exhausted"
+	}
+}
@@ -0,0 +1,168 @@
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	. "github.com/kamilsk/retry/tracing/otel"
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestTracer_Do(t *testing.T) {
+	failure := errors.New("failure")
+
+	t.Run("success", func(t *testing.T) {
+		tracer, exporter := setup()
+
+		var inner trace.SpanContext
+		err := tracer.Do(context.Background(), "fetch", func(ctx context.Context) error {
+			inner = trace.SpanContextFromContext(ctx)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		spans := exporter.GetSpans()
+		if len(spans) != 2 {
+			t.Fatalf("expected: %d, obtained: %d", 2, len(spans))
+		}
+		child, parent := spans[0], spans[1]
+		if parent.Name != "fetch" || child.Name != "fetch attempt" {
+			t.Errorf("unexpected names: %s, %s", parent.Name, child.Name)
+		}
+		if child.Parent.SpanID() != parent.SpanContext.SpanID() {
+			t.Error("attempt span expected to be a child of the call span")
+		}
+		if inner.SpanID() != child.SpanContext.SpanID() {
+			t.Error("action expected to receive the attempt span")
+		}
+		expect(t, parent.Attributes, AttemptsKey.Int64(1), OutcomeKey.String("success"))
+		expect(t, child.Attributes, AttemptKey.Int64(0))
+		if value(child.Attributes, DelayKey).Type() != attribute.FLOAT64 {
+			t.Error("attempt span expected to record the delay")
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		tracer, exporter := setup()
+
+		err := tracer.Do(context.Background(), "fetch", func(context.Context) error {
+			return failure
+		}, strategy.Limit(3), strategy.Wait(time.Millisecond))
+		if !errors.Is(err, retry.ErrExhausted) {
+			t.Fatalf("expected: %v, obtained: %v", retry.ErrExhausted, err)
+		}
+
+		spans := exporter.GetSpans()
+		if len(spans) != 4 {
+			t.Fatalf("expected: %d, obtained: %d", 4, len(spans))
+		}
+		parent := spans[3]
+		for i, child := range spans[:3] {
+			if child.Parent.SpanID() != parent.SpanContext.SpanID() {
+				t.Errorf("attempt span %d expected to be a child of the call span", i)
+			}
+			if child.Status.Code != codes.Error || len(child.Events) != 1 {
+				t.Errorf("attempt span %d expected to record the error", i)
+			}
+			expect(t, child.Attributes, AttemptKey.Int64(int64(i)))
+			if i > 0 && value(child.Attributes, DelayKey).AsFloat64() < time.Millisecond.Seconds() {
+				t.Errorf("attempt span %d expected to record the delay", i)
+			}
+		}
+		expect(t, parent.Attributes, AttemptsKey.Int64(3), OutcomeKey.String("exhausted"), StoppedByIndexKey.Int(0))
+		if name := value(parent.Attributes, StoppedByKey).AsString(); !strings.Contains(name, "strategy.Limit") {
+			t.Errorf("unexpected strategy: %s", name)
+		}
+		if parent.Status.Code != codes.Error {
+			t.Error("call span expected to record the error")
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		tracer, exporter := setup()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := tracer.Do(ctx, "fetch", func(context.Context) error {
+			cancel()
+			return failure
+		})
+		if !errors.Is(err, retry.ErrCancelled) {
+			t.Fatalf("expected: %v, obtained: %v", retry.ErrCancelled, err)
+		}
+
+		spans := exporter.GetSpans()
+		if len(spans) != 2 {
+			t.Fatalf("expected: %d, obtained: %d", 2, len(spans))
+		}
+		expect(t, spans[1].Attributes, AttemptsKey.Int64(1), OutcomeKey.String("cancelled"))
+		if value(spans[1].Attributes, StoppedByKey).Type() != attribute.INVALID {
+			t.Error("no strategy expected to stop the process")
+		}
+	})
+}
+
+func TestTracer_Go(t *testing.T) {
+	tracer, exporter := setup()
+
+	var made int
+	err := tracer.Go(signal(make(chan struct{})), "fetch", func(context.Context) error {
+		made++
+		if made < 2 {
+			return errors.New("failure")
+		}
+		return nil
+	}, strategy.Limit(3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected: %d, obtained: %d", 3, len(spans))
+	}
+	expect(t, spans[2].Attributes, AttemptsKey.Int64(2), OutcomeKey.String("success"))
+}
+
+// helpers
+
+type signal chan struct{}
+
+func (ch signal) Done() <-chan struct{} { return ch }
+func (ch signal) Err() error            { return nil }
+
+func setup() (Tracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return Tracer{Provider: provider}, exporter
+}
+
+func expect(t *testing.T, attributes []attribute.KeyValue, expected ...attribute.KeyValue) {
+	t.Helper()
+
+	for _, kv := range expected {
+		if obtained := value(attributes, kv.Key); obtained != kv.Value {
+			t.Errorf("%s expected: %v, obtained: %v", kv.Key, kv.Value.Emit(), obtained.Emit())
+		}
+	}
+}
+
+func value(attributes []attribute.KeyValue, key attribute.Key) attribute.Value {
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
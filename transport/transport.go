@@ -0,0 +1,265 @@
+// Package transport provides an http.RoundTripper that retries requests
+// using the retry strategies.
+package transport
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/backoff"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+// Transport is an http.RoundTripper that retries requests.
+//
+//  client := &http.Client{
+//  	Transport: &transport.Transport{
+//  		How: retry.How{
+//  			strategy.Limit(5),
+//  			strategy.RetryAfter(backoff.Exponential(100*time.Millisecond, 2), time.Minute),
+//  		},
+//  	},
+//  }
+//
+// It rewinds request bodies using the http.Request.GetBody and retries only
+// idempotent requests by default. Responses that are discarded to make
+// the next attempt are drained and closed. If all attempts result in
+// retriable responses, the last one is returned.
+//
+// The request context is passed as the breaker to the retry process,
+// so it can be configured by retry.With. Note that the retry.AttemptTimeout
+// cancels the attempt context as soon as the attempt is completed,
+// so the response body becomes unreadable.
+type Transport struct {
+	// Base is the underlying RoundTripper.
+	// The default is the http.DefaultTransport.
+	Base http.RoundTripper
+	// How is the strategies that define how the retry is performed.
+	// The default is DefaultHow.
+	How retry.How
+	// Idempotent reports whether the request can be retried.
+	// The default is IsIdempotent.
+	Idempotent func(*http.Request) bool
+	// Retriable reports whether the outcome of an attempt is retriable.
+	// The default is IsRetriable.
+	Retriable func(*http.Response, error) bool
+}
+
+// DefaultHow is used if the Transport has no strategies.
+var DefaultHow = retry.How{
+	strategy.Limit(3),
+	strategy.RetryAfter(backoff.Exponential(100*time.Millisecond, 2), 30*time.Second),
+}
+
+// RoundTrip executes a single HTTP transaction, repetitively, until
+// it receives a non-retriable response.
+func (transport *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		base      = transport.base()
+		retriable = true
+		rewind    = false
+		last      *http.Response
+		result    *http.Response
+	)
+
+	if !transport.idempotent(req) || !rewindable(req) {
+		return base.RoundTrip(req)
+	}
+
+	action := func(ctx context.Context) error {
+		if last != nil {
+			discard(last)
+			last = nil
+		}
+
+		attempt := req.Clone(ctx)
+		if rewind && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				retriable = false
+				return err
+			}
+			attempt.Body = body
+		}
+		rewind = true
+
+		resp, err := base.RoundTrip(attempt)
+		if retriable = transport.retriable(resp, err); err != nil {
+			return err
+		}
+		if retriable {
+			last = resp
+			return &StatusError{StatusCode: resp.StatusCode, Wait: wait(resp)}
+		}
+		result = resp
+		return nil
+	}
+	gate := func(_ retry.Breaker, attempt uint, _ error) bool {
+		return attempt == 0 || retriable
+	}
+
+	err := retry.Do(req.Context(), action, append(retry.How{gate}, transport.how()...)...)
+	if !rewind && req.Body != nil {
+		// the action was never performed, but the body must be closed anyway
+		_ = req.Body.Close()
+	}
+	if err == nil {
+		return result, nil
+	}
+	var status *StatusError
+	if last != nil && errors.As(err, &status) && !errors.Is(err, retry.ErrCancelled) {
+		return last, nil
+	}
+	if last != nil {
+		discard(last)
+	}
+	return nil, err
+}
+
+func (transport *Transport) base() http.RoundTripper {
+	if transport.Base == nil {
+		return http.DefaultTransport
+	}
+	return transport.Base
+}
+
+func (transport *Transport) how() retry.How {
+	if transport.How == nil {
+		return DefaultHow
+	}
+	return transport.How
+}
+
+func (transport *Transport) idempotent(req *http.Request) bool {
+	if transport.Idempotent == nil {
+		return IsIdempotent(req)
+	}
+	return transport.Idempotent(req)
+}
+
+func (transport *Transport) retriable(resp *http.Response, err error) bool {
+	if transport.Retriable == nil {
+		return IsRetriable(resp, err)
+	}
+	return transport.Retriable(resp, err)
+}
+
+// StatusError describes a retriable response.
+// It is passed to strategies and is compatible with the strategy.RetryAfter.
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Wait is the duration parsed from the Retry-After header.
+	Wait time.Duration
+}
+
+// Error returns a string representation of an error.
+func (err *StatusError) Error() string {
+	return fmt.Sprintf("transport: retriable response status %d %s", err.StatusCode, http.StatusText(err.StatusCode))
+}
+
+// RetryAfter returns the duration parsed from the Retry-After header.
+func (err *StatusError) RetryAfter() time.Duration { return err.Wait }
+
+// IsIdempotent reports whether the request is idempotent by its method
+// or by the presence of the Idempotency-Key or X-Idempotency-Key header.
+func IsIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, has := req.Header["Idempotency-Key"]
+	if !has {
+		_, has = req.Header["X-Idempotency-Key"]
+	}
+	return has
+}
+
+// RetriableStatuses are the HTTP status codes treated as retriable
+// by default.
+var RetriableStatuses = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooEarly,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// IsRetriable reports whether the outcome of an attempt is retriable.
+// Responses with the RetriableStatuses status codes are retriable.
+// Errors are retriable, except context and certificate verification errors.
+func IsRetriable(resp *http.Response, err error) bool {
+	if err != nil {
+		var (
+			authority x509.UnknownAuthorityError
+			hostname  x509.HostnameError
+			invalid   x509.CertificateInvalidError
+		)
+		return !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded) &&
+			!errors.As(err, &authority) &&
+			!errors.As(err, &hostname) &&
+			!errors.As(err, &invalid)
+	}
+	for _, status := range RetriableStatuses {
+		if resp.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRetryAfter parses the Retry-After header value in both
+// delta-seconds and HTTP-date forms relative to the given moment.
+// A date in the past results in zero duration.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		if seconds > int64(maxDuration/time.Second) {
+			return maxDuration, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+const (
+	drainLimit  = 4 << 10
+	maxDuration = time.Duration(1<<63 - 1)
+)
+
+func discard(resp *http.Response) {
+	_, _ = io.CopyN(io.Discard, resp.Body, drainLimit)
+	_ = resp.Body.Close()
+}
+
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func wait(resp *http.Response) time.Duration {
+	duration, _ := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return duration
+}
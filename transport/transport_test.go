@@ -0,0 +1,316 @@
+package transport_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/strategy"
+	. "github.com/kamilsk/retry/v5/transport"
+)
+
+func TestTransport(t *testing.T) {
+	t.Run("retry until success", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			if string(body) != "payload" {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if atomic.AddInt32(&calls, 1) < 3 {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = io.WriteString(rw, "done")
+		}))
+		defer server.Close()
+
+		tracker := &tracker{base: http.DefaultTransport}
+		client := &http.Client{Transport: &Transport{Base: tracker, How: retry.How{strategy.Limit(5)}}}
+		req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if body := read(resp); resp.StatusCode != http.StatusOK || body != "done" {
+			t.Errorf("unexpected response: %d %q", resp.StatusCode, body)
+		}
+		if calls != 3 || tracker.closed != 3 {
+			t.Errorf("unexpected number of calls: %d, closed bodies: %d", calls, tracker.closed)
+		}
+	})
+
+	t.Run("return last retriable response", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			rw.WriteHeader(http.StatusTooManyRequests)
+			_, _ = io.WriteString(rw, "slow down")
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: &Transport{How: retry.How{strategy.Limit(2)}}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if body := read(resp); resp.StatusCode != http.StatusTooManyRequests || body != "slow down" {
+			t.Errorf("unexpected response: %d %q", resp.StatusCode, body)
+		}
+		if calls != 2 {
+			t.Errorf("expected: %d, obtained: %d", 2, calls)
+		}
+	})
+
+	t.Run("non-retriable response", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			rw.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: &Transport{How: retry.How{strategy.Limit(5)}}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = read(resp)
+		if resp.StatusCode != http.StatusNotFound || calls != 1 {
+			t.Errorf("unexpected response: %d, %d calls", resp.StatusCode, calls)
+		}
+	})
+
+	t.Run("idempotency", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			rw.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: &Transport{How: retry.How{strategy.Limit(3)}}}
+
+		resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = read(resp)
+		if calls != 1 {
+			t.Errorf("expected: %d, obtained: %d", 1, calls)
+		}
+
+		req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+		req.Header.Set("Idempotency-Key", "key")
+		resp, err = client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = read(resp)
+		if calls != 4 {
+			t.Errorf("expected: %d, obtained: %d", 4, calls)
+		}
+
+		req, _ = http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("payload")))
+		resp, err = client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = read(resp)
+		if calls != 5 {
+			t.Errorf("expected: %d, obtained: %d", 5, calls)
+		}
+	})
+
+	t.Run("connection error", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		var calls int32
+		base := roundTripper(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return http.DefaultTransport.RoundTrip(req)
+		})
+		client := &http.Client{Transport: &Transport{Base: base, How: retry.How{strategy.Limit(3)}}}
+
+		_, err := client.Get(server.URL)
+		if !errors.Is(err, retry.ErrExhausted) || calls != 3 {
+			t.Errorf("unexpected result: %v, %d calls", err, calls)
+		}
+	})
+
+	t.Run("breaker cancel", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		tracker := &tracker{base: http.DefaultTransport}
+		client := &http.Client{Transport: &Transport{Base: tracker, How: retry.How{strategy.Wait(time.Hour)}}}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+		_, err := client.Do(req)
+		if !errors.Is(err, retry.ErrCancelled) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if tracker.closed != 1 {
+			t.Errorf("expected: %d, obtained: %d", 1, tracker.closed)
+		}
+	})
+
+	t.Run("close body without attempts", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var calls, closed int32
+		payload := &body{ReadCloser: io.NopCloser(strings.NewReader("payload")), closed: &closed}
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPut, "http://localhost", payload)
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("payload")), nil }
+
+		base := roundTripper(func(*http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, errors.New("unexpected call")
+		})
+		_, err := (&Transport{Base: base}).RoundTrip(req)
+		if !errors.Is(err, retry.ErrCancelled) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if calls != 0 || closed != 1 {
+			t.Errorf("unexpected result: %d calls, %d closed bodies", calls, closed)
+		}
+	})
+
+	t.Run("retry after", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				rw.Header().Set("Retry-After", "120")
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		var hint time.Duration
+		observe := func(_ retry.Breaker, attempt uint, err error) bool {
+			var status *StatusError
+			if errors.As(err, &status) {
+				hint = status.RetryAfter()
+			}
+			return true
+		}
+		client := &http.Client{Transport: &Transport{How: retry.How{strategy.Limit(2), observe}}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = read(resp)
+		if hint != 2*time.Minute {
+			t.Errorf("expected: %s, obtained: %s", 2*time.Minute, hint)
+		}
+	})
+}
+
+func TestIsRetriable(t *testing.T) {
+	tests := map[string]struct {
+		resp     *http.Response
+		err      error
+		expected bool
+	}{
+		"ok":                  {&http.Response{StatusCode: http.StatusOK}, nil, false},
+		"not found":           {&http.Response{StatusCode: http.StatusNotFound}, nil, false},
+		"request timeout":     {&http.Response{StatusCode: http.StatusRequestTimeout}, nil, true},
+		"too early":           {&http.Response{StatusCode: http.StatusTooEarly}, nil, true},
+		"too many requests":   {&http.Response{StatusCode: http.StatusTooManyRequests}, nil, true},
+		"internal error":      {&http.Response{StatusCode: http.StatusInternalServerError}, nil, true},
+		"not implemented":     {&http.Response{StatusCode: http.StatusNotImplemented}, nil, false},
+		"bad gateway":         {&http.Response{StatusCode: http.StatusBadGateway}, nil, true},
+		"service unavailable": {&http.Response{StatusCode: http.StatusServiceUnavailable}, nil, true},
+		"gateway timeout":     {&http.Response{StatusCode: http.StatusGatewayTimeout}, nil, true},
+		"http version":        {&http.Response{StatusCode: http.StatusHTTPVersionNotSupported}, nil, false},
+		"connection error":    {nil, errors.New("connection reset by peer"), true},
+		"canceled":            {nil, context.Canceled, false},
+		"deadline":            {nil, context.DeadlineExceeded, false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if obtained := IsRetriable(test.resp, test.err); test.expected != obtained {
+				t.Errorf("expected: %v, obtained: %v", test.expected, obtained)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		value    string
+		duration time.Duration
+		ok       bool
+	}{
+		"empty":        {"", 0, false},
+		"seconds":      {"120", 2 * time.Minute, true},
+		"zero":         {" 0 ", 0, true},
+		"negative":     {"-1", 0, false},
+		"huge":         {"99999999999999999", time.Duration(1<<63 - 1), true},
+		"date":         {"Fri, 01 Jan 2021 00:01:00 GMT", time.Minute, true},
+		"date in past": {"Thu, 31 Dec 2020 23:59:00 GMT", 0, true},
+		"invalid":      {"soon", 0, false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			duration, ok := ParseRetryAfter(test.value, now)
+			if test.duration != duration || test.ok != ok {
+				t.Errorf("expected: %s %v, obtained: %s %v", test.duration, test.ok, duration, ok)
+			}
+		})
+	}
+}
+
+// helpers
+
+func read(resp *http.Response) string {
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (fn roundTripper) RoundTrip(req *http.Request) (*http.Response, error) { return fn(req) }
+
+type tracker struct {
+	base   http.RoundTripper
+	closed int32
+}
+
+func (tracker *tracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := tracker.base.RoundTrip(req)
+	if err == nil {
+		resp.Body = &body{ReadCloser: resp.Body, closed: &tracker.closed}
+	}
+	return resp, err
+}
+
+type body struct {
+	io.ReadCloser
+	closed *int32
+}
+
+func (body *body) Close() error {
+	atomic.AddInt32(body.closed, 1)
+	return body.ReadCloser.Close()
+}
@@ -0,0 +1,76 @@
+package retry
+
+import "context"
+
+// DoValue takes the action and performs it, repetitively, until successful.
+// It returns the value produced by the successful attempt.
+//
+// Optionally, strategies may be passed that assess whether or not an attempt
+// should be made.
+func DoValue[T any](
+	breaker Breaker,
+	action func(context.Context) (T, error),
+	strategies ...func(Breaker, uint, error) bool,
+) (T, error) {
+	var result T
+	err := Do(breaker, func(ctx context.Context) error {
+		value, err := action(ctx)
+		if err == nil {
+			result = value
+		}
+		return err
+	}, strategies...)
+	return result, err
+}
+
+// GoValue takes the action and performs it, repetitively, until successful.
+// It differs from the DoValue method in that it performs the action in a goroutine.
+//
+// The value is owned by the goroutine until it is passed back, so if the breaker
+// fires first, GoValue returns the zero value and an attempt that completes later
+// is never written anywhere visible to the caller. In this case, the returned
+// CancelledError counts the running attempt and holds the errors of completed
+// ones the same way as Do does. The hooks are notified about the cancellation
+// and not about the events of the running attempt.
+//
+// Optionally, strategies may be passed that assess whether or not an attempt
+// should be made.
+func GoValue[T any](
+	breaker Breaker,
+	action func(context.Context) (T, error),
+	strategies ...func(Breaker, uint, error) bool,
+) (T, error) {
+	type outcome struct {
+		value T
+		err   error
+	}
+	var (
+		done  = make(chan outcome, 1)
+		state = track(breaker)
+	)
+
+	go func() {
+		var result outcome
+		defer func() {
+			if r := recover(); r != nil {
+				result = outcome{err: recovered(r)}
+			}
+			done <- result
+		}()
+		result.err = do(breaker, func(ctx context.Context) error {
+			value, err := action(ctx)
+			if err == nil {
+				result.value = value
+			}
+			return err
+		}, state, strategies)
+	}()
+
+	select {
+	case <-breaker.Done():
+		var zero T
+		return zero, state.interrupt(breaker.Err())
+	case result := <-done:
+		return result.value, result.err
+	}
+}
@@ -0,0 +1,144 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/kamilsk/retry/v5"
+	"github.com/kamilsk/retry/v5/strategy"
+)
+
+func TestDoValue(t *testing.T) {
+	tests := testCases
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts uint
+			action := func(ctx context.Context) (uint, error) {
+				attempts++
+				return attempts, test.action(ctx)
+			}
+			value, err := DoValue(test.breaker, action, test.strategies...)
+			if test.expected.attempts != attempts {
+				t.Errorf("expected: %d, obtained: %d", test.expected.attempts, attempts)
+			}
+			if !reflect.DeepEqual(test.expected.error, err) {
+				t.Errorf("expected: %#v, obtained: %#v", test.expected.error, err)
+			}
+			if err == nil && value != attempts {
+				t.Errorf("expected: %d, obtained: %d", attempts, value)
+			}
+			if err != nil && value != 0 {
+				t.Errorf("expected zero value, obtained: %d", value)
+			}
+		})
+	}
+
+	t.Run("value of the successful attempt", func(t *testing.T) {
+		var attempts int
+		action := func(context.Context) (int, error) {
+			attempts++
+			if attempts < 3 {
+				return -attempts, Error("failure")
+			}
+			return attempts, nil
+		}
+		value, err := DoValue(breaker(), action, strategy.Limit(5))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if value != 3 {
+			t.Errorf("expected: %d, obtained: %d", 3, value)
+		}
+	})
+}
+
+func TestGoValue(t *testing.T) {
+	tests := append(
+		testCases,
+		testCase{
+			"action call with error panic",
+			breaker(),
+			How{strategy.Wait(time.Hour)},
+			func(context.Context) error { panic(Error("failure")) },
+			expected{1, Error("failure")},
+		},
+		testCase{
+			"action call with non-error panic",
+			breaker(),
+			How{strategy.Wait(time.Hour)},
+			func(context.Context) error { panic("non-error") },
+			expected{1, fmt.Errorf("retry: unexpected panic: %#v", "non-error")},
+		},
+	)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts uint
+			action := func(ctx context.Context) (uint, error) {
+				attempts++
+				return attempts, test.action(ctx)
+			}
+			value, err := GoValue(test.breaker, action, test.strategies...)
+			if test.expected.attempts != attempts {
+				t.Errorf("expected: %d, obtained: %d", test.expected.attempts, attempts)
+			}
+			if !reflect.DeepEqual(test.expected.error, err) {
+				t.Errorf("expected: %#v, obtained: %#v", test.expected.error, err)
+			}
+			if err == nil && value != attempts {
+				t.Errorf("expected: %d, obtained: %d", attempts, value)
+			}
+		})
+	}
+
+	t.Run("running attempt on cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		var attempts int
+		action := func(context.Context) (int, error) {
+			if attempts++; attempts < 3 {
+				return 0, Error("failure")
+			}
+			cancel()
+			<-release
+			return attempts, nil
+		}
+		_, err := GoValue(ctx, action)
+
+		var cancelled *CancelledError
+		if !errors.As(err, &cancelled) {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		if cancelled.Attempts != 3 || cancelled.Err != Error("failure") {
+			t.Errorf("unexpected error: %#v", cancelled)
+		}
+	})
+
+	t.Run("no late write", func(t *testing.T) {
+		release := make(chan struct{})
+		action := func(context.Context) ([]int, error) {
+			<-release
+			return []int{1, 2, 3}, nil
+		}
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		value, err := GoValue(ctx, action)
+		close(release)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected: %#v, obtained: %#v", context.Canceled, err)
+		}
+		if value != nil {
+			t.Errorf("expected zero value, obtained: %v", value)
+		}
+	})
+}